@@ -0,0 +1,221 @@
+// Package capture saves terminal screens as ANSI text or standalone HTML
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/miguelmota/cryptocharts/theme"
+)
+
+// DefaultColor is the terminal's default foreground or background color
+const DefaultColor = -1
+
+// Cell is a screen cell. Colors are indexes into the 256 color palette or
+// DefaultColor
+type Cell struct {
+	Ch        rune
+	Fg        int
+	Bg        int
+	Bold      bool
+	Underline bool
+	Reverse   bool
+}
+
+// Screen is a grid of cells, row by row
+type Screen struct {
+	Width  int
+	Height int
+	Cells  []Cell
+}
+
+// NewScreen returns a blank screen
+func NewScreen(width, height int) *Screen {
+	s := &Screen{Width: width, Height: height, Cells: make([]Cell, width*height)}
+	for i := range s.Cells {
+		s.Cells[i] = Cell{Ch: ' ', Fg: DefaultColor, Bg: DefaultColor}
+	}
+	return s
+}
+
+// Set sets the cell at x, y, ignoring cells off the screen
+func (s *Screen) Set(x, y int, cell Cell) {
+	if x < 0 || y < 0 || x >= s.Width || y >= s.Height {
+		return
+	}
+	s.Cells[y*s.Width+x] = cell
+}
+
+// style returns the cell's colors and attributes without its rune
+func (c Cell) style() Cell {
+	c.Ch = 0
+	return c
+}
+
+// WriteANSI writes the screen as text with ANSI color escapes
+func (s *Screen) WriteANSI(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < s.Height; y++ {
+		var last Cell
+		for x := 0; x < s.Width; x++ {
+			cell := s.Cells[y*s.Width+x]
+			if x == 0 || cell.style() != last.style() {
+				bw.WriteString(ansiStyle(cell))
+			}
+			last = cell
+			bw.WriteRune(printable(cell.Ch))
+		}
+		bw.WriteString("\x1b[0m\n")
+	}
+	return bw.Flush()
+}
+
+// ansiStyle returns the SGR escape for the cell's style
+func ansiStyle(c Cell) string {
+	codes := []string{"0"}
+	if c.Bold {
+		codes = append(codes, "1")
+	}
+	if c.Underline {
+		codes = append(codes, "4")
+	}
+	if c.Reverse {
+		codes = append(codes, "7")
+	}
+	if c.Fg != DefaultColor {
+		codes = append(codes, ansiColor(c.Fg, 30))
+	}
+	if c.Bg != DefaultColor {
+		codes = append(codes, ansiColor(c.Bg, 40))
+	}
+	return fmt.Sprintf("\x1b[%sm", strings.Join(codes, ";"))
+}
+
+// ansiColor returns the SGR color code with base 30 for foreground and 40 for
+// background colors
+func ansiColor(color, base int) string {
+	if color < 8 {
+		return fmt.Sprint(base + color)
+	}
+	if color < 16 {
+		return fmt.Sprint(base + 60 + color - 8)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, color)
+}
+
+// WriteHTML writes the screen as a standalone HTML page with matching colors
+func (s *Screen) WriteHTML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>cryptocharts</title>
+<style>
+body { margin: 0; background: #000; }
+pre { margin: 0; padding: 8px; color: #e5e5e5; background: #000; font: 14px/1.2 Menlo, Consolas, "DejaVu Sans Mono", monospace; }
+</style>
+</head>
+<body>
+<pre>`)
+	for y := 0; y < s.Height; y++ {
+		x := 0
+		for x < s.Width {
+			cell := s.Cells[y*s.Width+x]
+			var text strings.Builder
+			for x < s.Width && s.Cells[y*s.Width+x].style() == cell.style() {
+				text.WriteRune(printable(s.Cells[y*s.Width+x].Ch))
+				x++
+			}
+			if css := htmlStyle(cell); css != "" {
+				fmt.Fprintf(bw, `<span style="%s">%s</span>`, css, html.EscapeString(text.String()))
+			} else {
+				bw.WriteString(html.EscapeString(text.String()))
+			}
+		}
+		bw.WriteString("\n")
+	}
+	bw.WriteString("</pre>\n</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// htmlStyle returns the inline CSS for the cell's style
+func htmlStyle(c Cell) string {
+	fg, bg := c.Fg, c.Bg
+	if c.Reverse {
+		fg, bg = bg, fg
+		if fg == DefaultColor {
+			fg = 0
+		}
+		if bg == DefaultColor {
+			bg = 7
+		}
+	}
+
+	var css []string
+	if fg != DefaultColor {
+		css = append(css, "color:"+Palette(fg))
+	}
+	if bg != DefaultColor {
+		css = append(css, "background:"+Palette(bg))
+	}
+	if c.Bold {
+		css = append(css, "font-weight:bold")
+	}
+	if c.Underline {
+		css = append(css, "text-decoration:underline")
+	}
+	return strings.Join(css, ";")
+}
+
+// Palette returns the hex RGB of a 256 color palette index
+func Palette(color int) string {
+	r, g, b := theme.PaletteRGB(color)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// printable replaces empty and control runes with spaces
+func printable(r rune) rune {
+	if r < ' ' || r == 0x7f {
+		return ' '
+	}
+	return r
+}
+
+// Save writes the screen to path as ANSI text for .ans and .txt files, or as
+// HTML for .html and .htm files
+func Save(s *Screen, path string) error {
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ans", ".txt":
+		write = s.WriteANSI
+	case ".html", ".htm":
+		write = s.WriteHTML
+	default:
+		return fmt.Errorf("unsupported capture format %q, available formats: .ans | .html", filepath.Ext(path))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Filename returns a timestamped capture filename with the extension. ie.
+// cryptocharts-20180102-150405.ans
+func Filename(ext string) string {
+	return fmt.Sprintf("cryptocharts-%s%s", time.Now().Format("20060102-150405"), ext)
+}
@@ -0,0 +1,38 @@
+package capture
+
+import (
+	termbox "github.com/nsf/termbox-go"
+)
+
+// FromTermbox returns the screen last flushed by termbox, as rendered by
+// termui
+func FromTermbox() *Screen {
+	width, height := termbox.Size()
+	cells := termbox.CellBuffer()
+
+	s := NewScreen(width, height)
+	for i, cell := range cells {
+		if i >= len(s.Cells) {
+			break
+		}
+		s.Cells[i] = Cell{
+			Ch:        cell.Ch,
+			Fg:        termboxColor(cell.Fg),
+			Bg:        termboxColor(cell.Bg),
+			Bold:      cell.Fg&termbox.AttrBold != 0,
+			Underline: cell.Fg&termbox.AttrUnderline != 0,
+			Reverse:   cell.Fg&termbox.AttrReverse != 0,
+		}
+	}
+	return s
+}
+
+// termboxColor returns the palette index of a termbox color attribute, which
+// is offset by one for the default color
+func termboxColor(attr termbox.Attribute) int {
+	color := int(attr & 0x1ff)
+	if color == 0 {
+		return DefaultColor
+	}
+	return color - 1
+}
@@ -0,0 +1,362 @@
+// Package chartimage renders price history line charts to SVG and PNG images
+package chartimage
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/miguelmota/cryptocharts/theme"
+)
+
+// Chart is a line chart of a [timestamp in ms, value] series, framed by a
+// border with the title in it like the terminal charts. Zero Background,
+// Text and Border colors default to the dark theme
+type Chart struct {
+	Title      string
+	Series     [][]float64
+	Overlays   []Overlay
+	Width      int
+	Height     int
+	Color      color.RGBA
+	Background color.RGBA
+	Text       color.RGBA
+	Border     color.RGBA
+}
+
+var (
+	background = color.RGBA{0x10, 0x12, 0x14, 0xff}
+	textColor  = color.RGBA{0xe8, 0xe8, 0xe8, 0xff}
+
+	// overlayColors are cycled through for the overlays
+	overlayColors = []color.RGBA{
+		{0xff, 0xd7, 0x00, 0xff},
+		{0x00, 0xd7, 0xff, 0xff},
+		{0xff, 0x5f, 0xff, 0xff},
+		{0xff, 0xff, 0xff, 0xff},
+	}
+)
+
+// Color returns the RGB color for a primary color name, 256 color index or
+// hex color
+func Color(name string) color.RGBA {
+	return ThemeColor(theme.Presets["dark"].WithAccent(name).Accent, color.RGBA{0x00, 0xcd, 0x00, 0xff})
+}
+
+// ThemeColor returns the RGB color of a theme color, or fallback for the
+// terminal's default color
+func ThemeColor(c theme.Color, fallback color.RGBA) color.RGBA {
+	r, g, b, ok := c.RGB()
+	if !ok {
+		return fallback
+	}
+	return color.RGBA{r, g, b, 0xff}
+}
+
+// palette returns the background, text, border and grid colors
+func (c *Chart) palette() (bg, text, border, grid color.RGBA) {
+	bg, text, border = c.Background, c.Text, c.Border
+	if bg.A == 0 {
+		bg = background
+	}
+	if text.A == 0 {
+		text = textColor
+	}
+	if border.A == 0 {
+		border = c.Color
+	}
+	return bg, text, border, mix(bg, text, 0.15)
+}
+
+// mix blends the colors, from a at 0 to b at 1
+func mix(a, b color.RGBA, t float64) color.RGBA {
+	blend := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA{blend(a.R, b.R), blend(a.G, b.G), blend(a.B, b.B), 0xff}
+}
+
+// Overlay is a line drawn over the price series. ie. a moving average
+type Overlay struct {
+	Kind   string // sma | ema | hilo
+	Period int    // number of points for moving averages
+}
+
+// ParseOverlays parses comma separated overlays. ie. sma20,ema50,hilo
+func ParseOverlays(value string) ([]Overlay, error) {
+	var overlays []Overlay
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		if item == "hilo" {
+			overlays = append(overlays, Overlay{Kind: "hilo"})
+			continue
+		}
+		if len(item) > 3 && (item[:3] == "sma" || item[:3] == "ema") {
+			period, err := strconv.Atoi(item[3:])
+			if err == nil && period > 0 {
+				overlays = append(overlays, Overlay{Kind: item[:3], Period: period})
+				continue
+			}
+		}
+		return nil, fmt.Errorf("invalid overlay %q, available overlays: smaN | emaN | hilo", item)
+	}
+	return overlays, nil
+}
+
+// Label returns the overlay legend label. ie. SMA 20
+func (o Overlay) Label() string {
+	if o.Kind == "hilo" {
+		return "HIGH/LOW"
+	}
+	return fmt.Sprintf("%s %d", strings.ToUpper(o.Kind), o.Period)
+}
+
+// Lines returns the overlay's lines for the series
+func (o Overlay) Lines(series [][]float64) [][][]float64 {
+	if len(series) == 0 {
+		return nil
+	}
+
+	switch o.Kind {
+	case "hilo":
+		min, max := bounds(series)
+		t0, t1 := series[0][0], series[len(series)-1][0]
+		return [][][]float64{
+			{{t0, max}, {t1, max}},
+			{{t0, min}, {t1, min}},
+		}
+	case "sma":
+		var line [][]float64
+		sum := 0.0
+		for i, p := range series {
+			sum += p[1]
+			if i >= o.Period {
+				sum -= series[i-o.Period][1]
+			}
+			if i >= o.Period-1 {
+				line = append(line, []float64{p[0], sum / float64(o.Period)})
+			}
+		}
+		return [][][]float64{line}
+	case "ema":
+		var line [][]float64
+		k := 2 / float64(o.Period+1)
+		ema := series[0][1]
+		for _, p := range series {
+			ema = p[1]*k + ema*(1-k)
+			line = append(line, []float64{p[0], ema})
+		}
+		return [][][]float64{line}
+	}
+	return nil
+}
+
+// bounds returns the min and max values of the series
+func bounds(series [][]float64) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range series {
+		min = math.Min(min, p[1])
+		max = math.Max(max, p[1])
+	}
+	return min, max
+}
+
+// text sizes in pixels
+const (
+	charWidth  = 12
+	charHeight = 14
+	padding    = 16
+)
+
+// layout is the plot area and axes of a chart
+type layout struct {
+	left, top, right, bottom float64
+	min, max                 float64
+	t0, t1                   float64
+	yTicks                   []tick
+	xTicks                   []tick
+}
+
+type tick struct {
+	value float64
+	label string
+}
+
+func (c *Chart) layout() layout {
+	l := layout{}
+	if len(c.Series) == 0 {
+		return l
+	}
+
+	l.t0, l.t1 = c.Series[0][0], c.Series[len(c.Series)-1][0]
+	l.min, l.max = bounds(c.Series)
+	if l.min == l.max {
+		l.min--
+		l.max++
+	}
+
+	step := niceStep((l.max - l.min) / 5)
+	l.min = math.Floor(l.min/step) * step
+	l.max = math.Ceil(l.max/step) * step
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	labelWidth := 0
+	for v := l.min; v <= l.max+step/2; v += step {
+		rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'f', decimals, 64), 64)
+		label := humanize.Commaf(rounded)
+		l.yTicks = append(l.yTicks, tick{v, label})
+		if len(label) > labelWidth {
+			labelWidth = len(label)
+		}
+	}
+
+	l.left = float64(padding + labelWidth*charWidth + padding/2)
+	l.top = float64(padding + charHeight + padding)
+	l.right = float64(c.Width - padding*2)
+	l.bottom = float64(c.Height - padding*2 - charHeight)
+
+	span := time.Duration(l.t1-l.t0) * time.Millisecond
+	layoutFormat := "Jan 02"
+	if span <= 2*24*time.Hour {
+		layoutFormat = "15:04"
+	} else if span > 365*24*time.Hour {
+		layoutFormat = "Jan 2006"
+	}
+	n := int((l.right - l.left) / float64((len(layoutFormat)+4)*charWidth))
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i <= n; i++ {
+		t := l.t0 + (l.t1-l.t0)*float64(i)/float64(n)
+		label := time.Unix(0, int64(t)*int64(time.Millisecond)).Format(layoutFormat)
+		l.xTicks = append(l.xTicks, tick{t, label})
+	}
+
+	return l
+}
+
+// x returns the x pixel for a timestamp
+func (l layout) x(t float64) float64 {
+	if l.t1 == l.t0 {
+		return l.left
+	}
+	return l.left + (t-l.t0)/(l.t1-l.t0)*(l.right-l.left)
+}
+
+// y returns the y pixel for a value
+func (l layout) y(v float64) float64 {
+	return l.top + (l.max-v)/(l.max-l.min)*(l.bottom-l.top)
+}
+
+// niceStep rounds a tick step up to 1, 2 or 5 times a power of ten
+func niceStep(step float64) float64 {
+	if step <= 0 {
+		return 1
+	}
+	pow := math.Pow(10, math.Floor(math.Log10(step)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if step <= m*pow {
+			return m * pow
+		}
+	}
+	return 10 * pow
+}
+
+// text anchors
+const (
+	anchorStart = iota
+	anchorEnd
+)
+
+// canvas is the drawing surface shared by the SVG and PNG renderers
+type canvas interface {
+	fill(x, y, w, h float64, c color.RGBA)
+	line(points [][2]float64, c color.RGBA, width float64)
+	text(x, y float64, s string, c color.RGBA, anchor int)
+}
+
+// draw draws the chart onto the canvas
+func (c *Chart) draw(cv canvas) {
+	w, h := float64(c.Width), float64(c.Height)
+	background, textColor, borderColor, gridColor := c.palette()
+	cv.fill(0, 0, w, h, background)
+
+	// border with the title in the top left like a termui block
+	inset := float64(padding) / 2
+	cv.line([][2]float64{{inset, inset}, {w - inset, inset}, {w - inset, h - inset}, {inset, h - inset}, {inset, inset}}, borderColor, 2)
+	if c.Title != "" {
+		titleX := float64(padding * 2)
+		cv.fill(titleX-charWidth/2, 0, float64(len(c.Title)*charWidth+charWidth), float64(charHeight+2), background)
+		cv.text(titleX, 1, c.Title, c.Color, anchorStart)
+	}
+
+	if len(c.Series) < 2 {
+		cv.text(w/2-float64(len("NO DATA")*charWidth)/2, h/2, "NO DATA", textColor, anchorStart)
+		return
+	}
+
+	l := c.layout()
+
+	for _, t := range l.yTicks {
+		y := l.y(t.value)
+		cv.line([][2]float64{{l.left, y}, {l.right, y}}, gridColor, 1)
+		cv.text(l.left-float64(padding)/2, y-charHeight/2, t.label, c.Color, anchorEnd)
+	}
+	// the first and last labels are aligned to the plot edges, skipping the
+	// labels before the last that they would overlap
+	lastStart := l.right - float64(len(l.xTicks[len(l.xTicks)-1].label)*charWidth)
+	labelEnd := 0.0
+	for i, t := range l.xTicks {
+		x := l.x(t.value)
+		width := float64(len(t.label) * charWidth)
+		tx := x - width/2
+		if i == 0 {
+			tx = x
+		} else if i == len(l.xTicks)-1 {
+			tx = lastStart
+		} else if tx+width+charWidth > lastStart {
+			continue
+		}
+		if tx < labelEnd+charWidth && i > 0 {
+			continue
+		}
+		labelEnd = tx + width
+		cv.line([][2]float64{{x, l.bottom}, {x, l.bottom + 4}}, c.Color, 1)
+		cv.text(tx, l.bottom+float64(padding)/2, t.label, c.Color, anchorStart)
+	}
+
+	// axes
+	cv.line([][2]float64{{l.left, l.top}, {l.left, l.bottom}, {l.right, l.bottom}}, c.Color, 1)
+
+	cv.line(c.points(l, c.Series), c.Color, 2)
+
+	legendX := l.right
+	legendY := l.top - float64(charHeight) - 4
+	for i := len(c.Overlays) - 1; i >= 0; i-- {
+		o := c.Overlays[i]
+		oc := overlayColors[i%len(overlayColors)]
+		for _, line := range o.Lines(c.Series) {
+			cv.line(c.points(l, line), oc, 1.5)
+		}
+		cv.text(legendX, legendY, o.Label(), oc, anchorEnd)
+		legendX -= float64((len(o.Label()) + 2) * charWidth)
+	}
+}
+
+// points maps a series to pixel points
+func (c *Chart) points(l layout, series [][]float64) [][2]float64 {
+	points := make([][2]float64, len(series))
+	for i, p := range series {
+		points[i] = [2]float64{l.x(p[0]), l.y(p[1])}
+	}
+	return points
+}
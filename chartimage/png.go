@@ -0,0 +1,67 @@
+package chartimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// WritePNG writes the chart as a PNG image
+func (c *Chart) WritePNG(w io.Writer) error {
+	cv := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))}
+	c.draw(cv)
+	return png.Encode(w, cv.img)
+}
+
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func (cv *pngCanvas) fill(x, y, w, h float64, c color.RGBA) {
+	r := image.Rect(int(x), int(y), int(math.Ceil(x+w)), int(math.Ceil(y+h)))
+	draw.Draw(cv.img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func (cv *pngCanvas) line(points [][2]float64, c color.RGBA, width float64) {
+	for i := 1; i < len(points); i++ {
+		cv.segment(points[i-1], points[i], c, width)
+	}
+}
+
+// segment draws a line segment by stamping squares of the line width along it
+func (cv *pngCanvas) segment(a, b [2]float64, c color.RGBA, width float64) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	steps := int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))))
+	if steps == 0 {
+		steps = 1
+	}
+	half := width / 2
+	for i := 0; i <= steps; i++ {
+		x := a[0] + dx*float64(i)/float64(steps)
+		y := a[1] + dy*float64(i)/float64(steps)
+		cv.fill(math.Floor(x-half+0.5), math.Floor(y-half+0.5), math.Max(1, math.Round(width)), math.Max(1, math.Round(width)), c)
+	}
+}
+
+func (cv *pngCanvas) text(x, y float64, s string, c color.RGBA, anchor int) {
+	runes := []rune(s)
+	if anchor == anchorEnd {
+		x -= float64(len(runes) * charWidth)
+	}
+	for i, r := range runes {
+		glyph := glyphFor(r)
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<uint(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := x + float64(i*charWidth+col*glyphScale)
+				py := y + float64(row*glyphScale)
+				cv.fill(px, py, glyphScale, glyphScale, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package chartimage
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// WriteSVG writes the chart as an SVG image
+func (c *Chart) WriteSVG(w io.Writer) error {
+	cv := &svgCanvas{}
+	fmt.Fprintf(&cv.buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="20">`+"\n", c.Width, c.Height, c.Width, c.Height)
+	c.draw(cv)
+	cv.buf.WriteString("</svg>\n")
+
+	_, err := w.Write(cv.buf.Bytes())
+	return err
+}
+
+type svgCanvas struct {
+	buf bytes.Buffer
+}
+
+func (cv *svgCanvas) fill(x, y, w, h float64, c color.RGBA) {
+	fmt.Fprintf(&cv.buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, hexColor(c))
+}
+
+func (cv *svgCanvas) line(points [][2]float64, c color.RGBA, width float64) {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+	}
+	fmt.Fprintf(&cv.buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"/>`+"\n", strings.Join(coords, " "), hexColor(c), width)
+}
+
+func (cv *svgCanvas) text(x, y float64, s string, c color.RGBA, anchor int) {
+	textAnchor := "start"
+	if anchor == anchorEnd {
+		textAnchor = "end"
+	}
+	fmt.Fprintf(&cv.buf, `<text x="%.1f" y="%.1f" fill="%s" text-anchor="%s">%s</text>`+"\n", x, y+charHeight, hexColor(c), textAnchor, html.EscapeString(s))
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/miguelmota/cryptocharts/theme"
+)
+
+// Config is the user config loaded from the config file
+type Config struct {
+	Columns      []string               `json:"columns"`
+	MetricsCoins []string               `json:"metricsCoins"`
+	Sinks        []SinkConfig           `json:"sinks"`
+	Theme        string                 `json:"theme"`
+	Themes       map[string]theme.Theme `json:"themes"`
+}
+
+// defaultConfigPath returns the default config file path
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cryptocharts.json")
+}
+
+// loadConfig loads the config file, a missing file results in an empty config
+func loadConfig(path string) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+)
+
+// runConvert runs the convert command. ie. cryptocharts convert 1.5 btc eth
+func runConvert(args []string) error {
+	conversion, err := data.ParseConversion(args)
+	if err != nil {
+		return err
+	}
+
+	// the converter fetches the coins it resolves
+	converter := data.NewConverter(nil)
+	result, err := converter.Convert(conversion.Amount, conversion.From, conversion.To)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s = %s\n", format.Amount(conversion.Amount, converter.Symbol(conversion.From)), format.Amount(data.Round(result), converter.Symbol(conversion.To)))
+	return nil
+}
+
+// calculator is the conversion calculator overlay, converting the input on
+// enter since converting can fetch coins and rates
+type calculator struct {
+	mu        sync.Mutex
+	visible   bool
+	input     string
+	converted string // the last converted input
+	result    string // the result of the converted input
+	limit     uint
+	color     string
+	converter *data.Converter
+}
+
+func newCalculator(limit uint, color string) *calculator {
+	return &calculator{limit: limit, color: color, input: "1 btc usd"}
+}
+
+// Visible returns true if the calculator is open
+func (c *calculator) Visible() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.visible
+}
+
+// Open opens the calculator
+func (c *calculator) Open() {
+	c.mu.Lock()
+	c.visible = true
+	c.mu.Unlock()
+}
+
+// Refresh refetches the coins the calculator converts between, and converts
+// the last converted input again, or the input when there's none yet
+func (c *calculator) Refresh() error {
+	coins, err := data.FetchCoins(0, int(c.limit))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.converter = data.NewConverter(coins)
+	input := c.converted
+	if input == "" {
+		input = c.input
+	}
+	c.mu.Unlock()
+
+	c.convert(input)
+	return nil
+}
+
+// Convert converts the input
+func (c *calculator) Convert() {
+	c.mu.Lock()
+	input := c.input
+	c.mu.Unlock()
+
+	c.convert(input)
+}
+
+// convert converts the input and keeps the result, without holding the lock
+// while coins and rates are fetched
+func (c *calculator) convert(input string) {
+	c.mu.Lock()
+	converter := c.converter
+	c.mu.Unlock()
+	if converter == nil {
+		return
+	}
+
+	result, err := converter.ConvertInput(input)
+	if err != nil {
+		result = err.Error()
+	}
+
+	c.mu.Lock()
+	c.converted, c.result = input, result
+	c.mu.Unlock()
+}
+
+// HandleKey edits the input, returning false for keys it doesn't handle.
+// Enter is handled by converting with Convert
+func (c *calculator) HandleKey(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case key == "<escape>":
+		c.visible = false
+	case key == "<enter>":
+	case key == "<backspace>" || key == "C-8":
+		if len(c.input) > 0 {
+			c.input = c.input[:len(c.input)-1]
+		}
+	case key == "C-u":
+		c.input = ""
+	case key == "<space>":
+		c.input += " "
+	case len(key) == 1:
+		c.input += key
+	default:
+		return false
+	}
+	return true
+}
+
+// Render renders the calculator over the current view
+func (c *calculator) Render() {
+	c.mu.Lock()
+	visible, input, converted, result := c.visible, c.input, c.converted, c.result
+	c.mu.Unlock()
+
+	if !visible {
+		return
+	}
+
+	hint := "fg-" + widgets.Theme().Text.BasicName()
+	switch {
+	case converted == "":
+		result = "loading..."
+	case converted != input:
+		result = fmt.Sprintf("[<enter>](%s) to convert", hint)
+	}
+
+	width := 50
+	if width > ui.TermWidth() {
+		width = ui.TermWidth()
+	}
+
+	primaryColor := widgets.Color(c.color)
+	par := ui.NewPar(fmt.Sprintf("> %s_\n= %s\n\n[<amount> <from> <to>](%s)  [esc](%s) to close", input, result, hint, hint))
+	par.Width = width
+	par.Height = 6
+	par.X = (ui.TermWidth() - width) / 2
+	par.Y = (ui.TermHeight() - par.Height) / 2
+	par.TextFgColor = primaryColor
+	par.BorderFg = widgets.BorderColor()
+	par.BorderLabel = "Convert"
+	par.BorderLabelFg = primaryColor
+	ui.Render(par)
+}
@@ -3,465 +3,528 @@ package main
 import (
 	"flag"
 	"fmt"
-	"strconv"
+	"log"
+	"net/http"
+	"os"
 	"strings"
 	"time"
 
-	humanize "github.com/dustin/go-humanize"
 	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/capture"
+	"github.com/miguelmota/cryptocharts/dash"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
 	table "github.com/miguelmota/cryptocharts/table"
-	cmc "github.com/miguelmota/go-coinmarketcap"
+	"github.com/miguelmota/cryptocharts/theme"
+	"github.com/miguelmota/cryptocharts/widgets"
+	termbox "github.com/nsf/termbox-go"
 )
 
-// RenderChartDash renders chart dash
-func RenderChartDash(coin string, dateRange string, color string, lineChartHeight uint) error {
-	if coin == "" {
-		coin = "bitcoin"
-	}
+// RenderTable renders table
+func RenderTable(t theme.Theme, depth int, limit uint, refresh uint, columns []string, loadAll bool, capturePath string) error {
+	tbl := table.New(&table.Options{
+		Theme:   t,
+		Depth:   depth,
+		Limit:   limit,
+		Refresh: refresh,
+		Columns: columns,
+		LoadAll: loadAll,
+		Capture: capturePath,
+	})
+	return tbl.Render()
+}
 
-	if dateRange == "" {
-		dateRange = "7d"
+// initUI sets the widget theme and starts termui, with 256 color output when
+// the terminal supports it
+func initUI(t theme.Theme, depth int) error {
+	widgets.SetTheme(t, depth)
+	err := ui.Init()
+	if err != nil {
+		return err
+	}
+	if depth >= theme.Colors256 {
+		termbox.SetOutputMode(termbox.Output256)
 	}
+	return nil
+}
 
-	primaryColor := getColor(color)
+// enableMouse turns on termbox mouse events, with xterm's any event tracking
+// so moving the pointer reports motion without a button held
+func enableMouse() {
+	termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+	fmt.Print("\x1b[?1003h")
+}
 
-	var (
-		oneMinute int64 = 60
-		oneHour         = oneMinute * 60
-		oneDay          = oneHour * 24
-		oneWeek         = oneDay * 7
-		oneMonth        = oneDay * 30
-		oneYear         = oneDay * 365
-	)
+// disableMouse turns off the any event tracking, which termbox doesn't reset
+// on close
+func disableMouse() {
+	fmt.Print("\x1b[?1003l")
+}
 
-	now := time.Now()
-	secs := now.Unix()
-	start := secs - oneDay
-	end := secs
+// saveScreen saves the rendered termui screen to path, or to a timestamped
+// ANSI file when path is empty, and returns the path
+func saveScreen(path string) (string, error) {
+	if path == "" {
+		path = capture.Filename(".ans")
+	}
+	return path, capture.Save(capture.FromTermbox(), path)
+}
 
-	dateNumber, err := strconv.ParseInt(dateRange[0:len(dateRange)-1], 10, 64)
+// renderStatus renders a status message over the bottom row until the next
+// render
+func renderStatus(msg string, color string) {
+	status := ui.NewPar(msg)
+	status.Border = false
+	status.Height = 1
+	status.Width = len(msg)
+	status.Y = ui.TermHeight() - 1
+	status.TextFgColor = widgets.Color(color)
+	ui.Render(status)
+}
 
-	if err != nil {
-		dateNumber = 30
-	}
+func main() {
+	var coin = flag.String("coin", "bitcoin", "Cryptocurrency id, symbol or name. ie. bitcoin | ETH | \"bitcoin cash\" | etc...")
+	var dateRange = flag.String("date", "7d", "Chart date range, a duration or a named range. ie. 1h | 1d | 7d | 2w | 3m | 1y | 1y6m | ytd | all | since-ath")
+	var fromDate = flag.String("from", "", "Chart the period from the date instead of the -date range. ie. 2024-01-01 | \"2024-01-01 09:30\"")
+	var toDate = flag.String("to", "", "End of the -from period, defaults to now. ie. 2024-03-31")
+	var color = flag.String("color", "", "Primary color, overriding the theme's accent and border colors. ie. green | cyan | magenta | red | yellow | white | 33 | #ff8800")
+	var themeName = flag.String("theme", "", "Color theme, a preset or a theme from the config file. ie. dark | light | solarized | colorblind")
+	var lineChartHeight = flag.Uint("chart-height", 20, "Line chart height: .ie. 15 | 20 | 25 | 30")
+	var showTable = flag.Bool("table", false, "Show the top cryptocurrencies in a table, -limit at a time, loading more as you scroll.")
+	var limit = flag.Uint("limit", 100, "Number of cryptocurrencies to load per page for table, more are loaded as you scroll. ie. 10 | 25 | 50 | 100")
+	var loadAll = flag.Bool("all", false, "Load all cryptocurrencies for table up front.")
+	var refresh = flag.Uint("refresh", 60, "How often to refetch data in seconds: .ie. 30, 60")
+	var showGlobalMarketDash = flag.Bool("global", false, "Show global market data and history charts for the -date range.")
+	var columns = flag.String("columns", "", fmt.Sprintf("Comma separated table columns in display order. ie. %s", strings.Join(table.ColumnIDs(), " | ")))
+	var configPath = flag.String("config", defaultConfigPath(), "Path to JSON config file.")
+	var showHeatmap = flag.Bool("heatmap", false, "Show a market cap heatmap and market share of the top -limit cryptocurrencies.")
+	var changeWindow = flag.String("change", "24h", "Heatmap and top movers % change window. ie. 1h | 24h | 7d")
+	var moversCount = flag.Uint("movers", 5, "Number of top gainers and losers to show on the global dash, 0 to hide.")
+	var minCap = flag.Float64("min-cap", 0, "Minimum market cap in USD for top gainers and losers.")
+	var serve = flag.String("serve", "", "Run headless and serve the data as a JSON API on the address. ie. :8080")
+	var daemon = flag.Bool("daemon", false, "Run headless and write snapshots of the top -limit cryptocurrencies to the sinks in the config file.")
+	var metricsAddr = flag.String("metrics", "", "Run headless and serve Prometheus metrics on the address. ie. :9100")
+	var metricsCoins = flag.String("metrics-coins", "", "Comma separated coins to export metrics for, defaults to the top -limit coins. ie. bitcoin,ethereum")
+	var minVolume = flag.Float64("min-volume", 0, "Minimum 24 hour volume in USD for top gainers and losers.")
+	var exportPath = flag.String("export", "", "Export the -coin price history chart for the -date range to an image file and exit. ie. chart.svg | chart.png")
+	var exportSize = flag.String("size", "1200x600", "Exported chart image size in pixels. ie. 800x400 | 1200x600")
+	var overlays = flag.String("overlay", "", "Comma separated overlays for the exported chart. ie. sma20 | ema50 | hilo")
+	var capturePath = flag.String("capture", "", "Save the first rendered screen of the view to a file and exit. ie. screen.ans | screen.html")
+	var tickerCoins = flag.String("ticker", "", "Print a one line summary of the comma separated coins on each refresh, for status bars and prompts. ie. bitcoin,ETH")
+	var tickerFormat = flag.String("ticker-format", defaultTickerFormat, "Ticker text/template for each coin, over the coin fields and the usd, change, percent and comma functions.")
+	var tickerTitle = flag.Bool("ticker-title", false, "Set the terminal window title to the ticker instead of printing it.")
+	var plain = flag.Bool("plain", false, "Print the ticker without colors.")
+	var once = flag.Bool("once", false, "Print the ticker once and exit.")
+	var webAddr = flag.String("web", "", "Run headless and serve the web dashboard on the address. ie. :8081")
+	var correlationCoins = flag.String("correlation", "", "Show a matrix of the correlation between the returns of the comma separated coins over the -date range. ie. bitcoin,ethereum,litecoin")
+	var interval = flag.String("interval", "", "Correlation return interval, defaults to the resolution of the history. ie. 1h | 4h | 1d")
+	var window = flag.String("window", "", "Correlation rolling window, defaults to the whole -date range. ie. 1d | 7d | 30d")
+	var gridCoins = flag.String("grid", "", "Show a grid of panels with the price, 24 hour % change and -date range chart of the comma separated coins. ie. bitcoin,ethereum,litecoin")
+
+	flag.Parse()
 
-	dateType := dateRange[len(dateRange)-1:]
-
-	if dateType == "n" {
-		start = secs - (oneMinute * dateNumber)
-	} else if dateType == "h" {
-		start = secs - (oneHour * dateNumber)
-	} else if dateType == "d" {
-		start = secs - (oneDay * dateNumber)
-	} else if dateType == "w" {
-		start = secs - (oneWeek * dateNumber)
-	} else if dateType == "m" {
-		start = secs - (oneMonth * dateNumber)
-	} else if dateType == "y" {
-		start = secs - (oneYear * dateNumber)
-	} else {
-		dateType = "d"
+	if flag.Arg(0) == "convert" {
+		err := runConvert(flag.Args()[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	coinInfo, err := cmc.GetCoinData(coin)
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		panic(err)
+	}
 
+	err = data.ValidateChangeWindow(*changeWindow)
 	if err != nil {
-		return err
+		log.Fatal(err)
+	}
+	if *refresh == 0 {
+		log.Fatal("-refresh must be at least 1 second")
 	}
 
-	graphData, err := cmc.GetCoinGraphData(coin, start, end)
+	if *columns != "" {
+		config.Columns = splitList(*columns)
+	}
 
+	if *themeName != "" {
+		config.Theme = *themeName
+	}
+	t, err := theme.Get(config.Theme, config.Themes)
 	if err != nil {
-		return err
+		log.Fatal(err)
 	}
-
-	sinps := (func() []float64 {
-		n := len(graphData.PriceUsd)
-		ps := make([]float64, n)
-		for i := range graphData.PriceUsd {
-			ps[i] = graphData.PriceUsd[i][1]
+	if *color != "" {
+		t = t.WithAccent(*color)
+		err = t.Validate()
+		if err != nil {
+			log.Fatal(err)
 		}
-		return ps
-	})()
-
-	if lineChartHeight == 0 {
-		lineChartHeight = 20
 	}
+	depth := theme.Depth()
 
-	lc1 := ui.NewLineChart()
-	lc1.Data = sinps
-	lc1.Width = 100
-	lc1.Height = int(lineChartHeight)
-	lc1.X = 0
-	lc1.Y = 7
-	lc1.AxesColor = primaryColor
-	lc1.LineColor = primaryColor | ui.AttrBold
-	lc1.BorderFg = primaryColor
-	lc1.BorderLabel = fmt.Sprintf("%s %s: %d%s", coinInfo.Symbol, "Price History", dateNumber, strings.ToUpper(dateType))
-	lc1.BorderLabelFg = primaryColor
-
-	par0 := ui.NewPar(fmt.Sprintf("%.2f%%", coinInfo.PercentChange1h))
-	par0.Height = 3
-	par0.Width = 20
-	par0.Y = 1
-	par0.TextFgColor = ui.ColorGreen
-	par0.BorderLabel = "% Change (1H)"
-	par0.BorderLabelFg = ui.ColorGreen
-	par0.BorderFg = ui.ColorGreen
-	if coinInfo.PercentChange1h < 0 {
-		par0.TextFgColor = ui.ColorRed
-		par0.BorderFg = ui.ColorRed
-		par0.BorderLabelFg = ui.ColorRed
+	if *metricsCoins != "" {
+		config.MetricsCoins = splitList(*metricsCoins)
 	}
 
-	par1 := ui.NewPar(fmt.Sprintf("%.2f%%", coinInfo.PercentChange24h))
-	par1.Height = 3
-	par1.Width = 20
-	par1.Y = 1
-	par1.TextFgColor = ui.ColorGreen
-	par1.BorderLabel = "% Change (24H)"
-	par1.BorderFg = ui.ColorGreen
-	if coinInfo.PercentChange24h < 0 {
-		par1.TextFgColor = ui.ColorRed
-		par1.BorderFg = ui.ColorRed
-		par1.BorderLabelFg = ui.ColorRed
+	if *refresh == 0 {
+		var i uint = 60
+		refresh = &i
 	}
 
-	par2 := ui.NewPar(fmt.Sprintf("%.2f%%", coinInfo.PercentChange7d))
-	par2.Height = 3
-	par2.Width = 20
-	par2.Y = 1
-	par2.TextFgColor = ui.ColorGreen
-	par2.BorderLabel = "% Change (7D)"
-	par2.BorderFg = ui.ColorGreen
-	if coinInfo.PercentChange7d < 0 {
-		par2.TextFgColor = ui.ColorRed
-		par2.BorderFg = ui.ColorRed
-		par2.BorderLabelFg = ui.ColorRed
+	// the range is parsed on each render so relative ranges end now
+	parseRange := func() (daterange.Range, error) {
+		if *fromDate != "" {
+			return daterange.Between(*fromDate, *toDate, time.Now())
+		}
+		if *toDate != "" {
+			return daterange.Range{}, fmt.Errorf("-to requires -from")
+		}
+		return daterange.ParseRange(*dateRange)
 	}
-
-	par3 := ui.NewPar(fmt.Sprintf("%s", coinInfo.Name))
-	par3.Height = 3
-	par3.Width = 20
-	par3.Y = 1
-	par3.TextFgColor = ui.ColorWhite
-	par3.BorderLabel = "Name"
-	par3.BorderLabelFg = primaryColor
-	par3.BorderFg = primaryColor
-
-	par4 := ui.NewPar(fmt.Sprintf("$%s", humanize.Commaf(coinInfo.PriceUsd)))
-	par4.Height = 3
-	par4.Width = 20
-	par4.Y = 1
-	par4.TextFgColor = ui.ColorWhite
-	par4.BorderLabel = "Price (USD)"
-	par4.BorderLabelFg = primaryColor
-	par4.BorderFg = primaryColor
-
-	par5 := ui.NewPar(fmt.Sprintf("%s", coinInfo.Symbol))
-	par5.Height = 3
-	par5.Width = 20
-	par5.Y = 1
-	par5.TextFgColor = ui.ColorWhite
-	par5.BorderLabel = "Symbol"
-	par5.BorderLabelFg = primaryColor
-	par5.BorderFg = primaryColor
-
-	par6 := ui.NewPar(humanize.Comma(int64(coinInfo.Rank)))
-	par6.Height = 3
-	par6.Width = 20
-	par6.Y = 1
-	par6.TextFgColor = ui.ColorWhite
-	par6.BorderLabel = "Rank"
-	par6.BorderLabelFg = primaryColor
-	par6.BorderFg = primaryColor
-
-	par7 := ui.NewPar(fmt.Sprintf("$%s", humanize.Commaf(coinInfo.MarketCapUsd)))
-	par7.Height = 3
-	par7.Width = 20
-	par7.Y = 1
-	par7.TextFgColor = ui.ColorWhite
-	par7.BorderLabel = "Market Cap"
-	par7.BorderLabelFg = primaryColor
-	par7.BorderFg = primaryColor
-
-	par8 := ui.NewPar(fmt.Sprintf("$%s", humanize.Commaf(coinInfo.Usd24hVolume)))
-	par8.Height = 3
-	par8.Width = 20
-	par8.Y = 1
-	par8.TextFgColor = ui.ColorWhite
-	par8.BorderLabel = "Volume (24H)"
-	par8.BorderLabelFg = primaryColor
-	par8.BorderFg = primaryColor
-
-	par9 := ui.NewPar(fmt.Sprintf("%s %s", humanize.Commaf(coinInfo.AvailableSupply), coinInfo.Symbol))
-	par9.Height = 3
-	par9.Width = 20
-	par9.Y = 1
-	par9.TextFgColor = ui.ColorWhite
-	par9.BorderLabel = "Circulating Supply"
-	par9.BorderLabelFg = primaryColor
-	par9.BorderFg = primaryColor
-
-	par10 := ui.NewPar(fmt.Sprintf("%s %s", humanize.Commaf(coinInfo.TotalSupply), coinInfo.Symbol))
-	par10.Height = 3
-	par10.Width = 20
-	par10.Y = 1
-	par10.TextFgColor = ui.ColorWhite
-	par10.BorderLabel = "Total Supply"
-	par10.BorderLabelFg = primaryColor
-	par10.BorderFg = primaryColor
-
-	unix, err := strconv.ParseInt(coinInfo.LastUpdated, 10, 64)
-
+	_, err = parseRange()
 	if err != nil {
-		return err
+		log.Fatal(err)
 	}
 
-	par11 := ui.NewPar(time.Unix(unix, 0).Format("15:04:05 Jan 02"))
-	par11.Height = 3
-	par11.Width = 20
-	par11.Y = 1
-	par11.TextFgColor = ui.ColorWhite
-	par11.BorderLabel = "Last Updated"
-	par11.BorderLabelFg = primaryColor
-	par11.BorderFg = primaryColor
-
-	// reset
-	ui.Body.Rows = ui.Body.Rows[:0]
-
-	// add grid rows and columns
-	ui.Body.AddRows(
-		ui.NewRow(
-			ui.NewCol(2, 0, par3),
-			ui.NewCol(2, 0, par5),
-			ui.NewCol(2, 0, par4),
-			ui.NewCol(2, 0, par0),
-			ui.NewCol(2, 0, par1),
-			ui.NewCol(2, 0, par2),
-		),
-		ui.NewRow(
-			ui.NewCol(2, 0, par6),
-			ui.NewCol(2, 0, par7),
-			ui.NewCol(2, 0, par8),
-			ui.NewCol(2, 0, par9),
-			ui.NewCol(2, 0, par10),
-			ui.NewCol(2, 0, par11),
-		),
-		ui.NewRow(
-			ui.NewCol(12, 0, lc1),
-		),
-	)
-
-	// calculate layout
-	ui.Body.Align()
-
-	// render to terminal
-	ui.Render(ui.Body)
-
-	return nil
-}
-
-// RenderGlobalMarketDash renders global market dash
-func RenderGlobalMarketDash(color string) error {
-	primaryColor := getColor(color)
-
-	marketData, err := cmc.GetMarketData()
+	if *exportPath != "" {
+		r, _ := parseRange()
+		id, err := resolveCoin(*coin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = exportChart(*exportPath, id, r, t, *exportSize, *overlays)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	if err != nil {
-		return err
+	if *tickerCoins != "" {
+		refreshInterval := time.Duration(int64(*refresh)) * time.Second
+		color := !*plain && os.Getenv("NO_COLOR") == ""
+		coinIDs, err := resolveCoins(splitList(*tickerCoins))
+		if err != nil {
+			log.Fatal(err)
+		}
+		tk, err := NewTicker(coinIDs, *tickerFormat, t, depth, color, *tickerTitle, refreshInterval, os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = tk.Run(*once)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	par0 := ui.NewPar(fmt.Sprintf("$%s", humanize.Commaf(marketData.TotalMarketCapUsd)))
-	par0.Height = 3
-	par0.Width = 20
-	par0.Y = 1
-	par0.TextFgColor = ui.ColorWhite
-	par0.BorderLabel = "Total Market Cap (USD)"
-	par0.BorderLabelFg = primaryColor
-	par0.BorderFg = primaryColor
-
-	par1 := ui.NewPar(fmt.Sprintf("$%s", humanize.Commaf(marketData.Total24hVolumeUsd)))
-	par1.Height = 3
-	par1.Width = 20
-	par1.Y = 1
-	par1.TextFgColor = ui.ColorWhite
-	par1.BorderLabel = "Total Volume (24H)"
-	par1.BorderLabelFg = primaryColor
-	par1.BorderFg = primaryColor
-
-	par2 := ui.NewPar(fmt.Sprintf("%.2f%%", marketData.BitcoinPercentageOfMarketCap))
-	par2.Height = 3
-	par2.Width = 20
-	par2.Y = 1
-	par2.TextFgColor = ui.ColorWhite
-	par2.BorderLabel = "% Bitcoin Dominance"
-	par2.BorderLabelFg = primaryColor
-	par2.BorderFg = primaryColor
-
-	par3 := ui.NewPar(fmt.Sprintf("%s", humanize.Comma(int64(marketData.ActiveCurrencies))))
-	par3.Height = 3
-	par3.Width = 20
-	par3.Y = 1
-	par3.TextFgColor = ui.ColorWhite
-	par3.BorderLabel = "Active Currencies"
-	par3.BorderLabelFg = primaryColor
-	par3.BorderFg = primaryColor
-
-	par4 := ui.NewPar(fmt.Sprintf("%s", humanize.Comma(int64(marketData.ActiveAssets))))
-	par4.Height = 3
-	par4.Width = 20
-	par4.Y = 1
-	par4.TextFgColor = ui.ColorWhite
-	par4.BorderLabel = "Active Assets"
-	par4.BorderLabelFg = primaryColor
-	par4.BorderFg = primaryColor
-
-	par5 := ui.NewPar(fmt.Sprintf("%s", humanize.Comma(int64(marketData.ActiveMarkets))))
-	par5.Height = 3
-	par5.Width = 20
-	par5.Y = 1
-	par5.TextFgColor = ui.ColorWhite
-	par5.BorderLabel = "Active Markets"
-	par5.BorderLabelFg = primaryColor
-	par5.BorderFg = primaryColor
-
-	// reset
-	ui.Body.Rows = ui.Body.Rows[:0]
-
-	// add grid rows and columns
-	ui.Body.AddRows(
-		ui.NewRow(
-			ui.NewCol(2, 0, par0),
-			ui.NewCol(2, 0, par1),
-			ui.NewCol(2, 0, par2),
-			ui.NewCol(2, 0, par3),
-			ui.NewCol(2, 0, par4),
-			ui.NewCol(2, 0, par5),
-		),
-	)
-
-	// calculate layout
-	ui.Body.Align()
-
-	// render to terminal
-	ui.Render(ui.Body)
+	// headless modes
+	if *serve != "" || *webAddr != "" || *metricsAddr != "" || *daemon {
+		refreshInterval := time.Duration(int64(*refresh)) * time.Second
+		errs := make(chan error)
+		if *serve != "" || *webAddr != "" {
+			// the JSON API and web dashboard share one cached feed
+			server := NewServer(*limit, refreshInterval)
+			err := server.Start()
+			if err != nil {
+				log.Fatal(err)
+			}
+			if *serve != "" {
+				go func() {
+					log.Printf("serving JSON API on %s", *serve)
+					errs <- http.ListenAndServe(*serve, server.Handler())
+				}()
+			}
+			if *webAddr != "" {
+				go func() {
+					log.Printf("serving web dashboard on %s", *webAddr)
+					errs <- http.ListenAndServe(*webAddr, server.WebHandler())
+				}()
+			}
+		}
+		if *metricsAddr != "" {
+			config.MetricsCoins, err = resolveCoins(config.MetricsCoins)
+			if err != nil {
+				log.Fatal(err)
+			}
+			go func() {
+				errs <- NewExporter(config.MetricsCoins, *limit, refreshInterval).ListenAndServe(*metricsAddr)
+			}()
+		}
+		if *daemon {
+			d, err := NewDaemon(config.Sinks, *limit, refreshInterval)
+			if err != nil {
+				log.Fatal(err)
+			}
+			go func() {
+				errs <- d.Run()
+			}()
+		}
+		log.Fatal(<-errs)
+	}
 
-	return nil
-}
+	showGrid := *gridCoins != "" && !*showTable && !*showGlobalMarketDash && !*showHeatmap
+	showCorrelation := *correlationCoins != "" && !*showTable && !*showGlobalMarketDash && !*showHeatmap && !showGrid
+	showChart := !*showTable && !*showGlobalMarketDash && !*showHeatmap && !showGrid && !showCorrelation
 
-// RenderTable renders table
-func RenderTable(color string, limit uint, refresh uint) error {
-	t := table.New(&table.Options{
-		Color:   color,
-		Limit:   limit,
-		Refresh: refresh,
-	})
-	return t.Render()
-}
-
-// GetColor gets primary color
-func getColor(color string) ui.Attribute {
-	if color == "" {
-		color = "green"
+	// the chart and grid dashes take coins as ids, symbols or names, resolved
+	// before the terminal is taken over so a chooser can be shown
+	if showChart {
+		*coin, err = resolveCoin(*coin)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
-
-	primaryColor := ui.ColorGreen
-
-	if color == "green" {
-		primaryColor = ui.ColorGreen
-	} else if color == "cyan" || color == "blue" {
-		primaryColor = ui.ColorCyan
-	} else if color == "magenta" || color == "pink" || color == "purple" {
-		primaryColor = ui.ColorMagenta
-	} else if color == "white" {
-		primaryColor = ui.ColorWhite
-	} else if color == "red" {
-		primaryColor = ui.ColorRed
-	} else if color == "yellow" || color == "orange" {
-		primaryColor = ui.ColorYellow
+	var gridIDs []string
+	if showGrid {
+		gridIDs, err = resolveCoins(splitList(*gridCoins))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	var correlationIDs []string
+	var returnInterval, rollingWindow int64
+	if showCorrelation {
+		correlationIDs, err = resolveCoins(splitList(*correlationCoins))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *interval != "" {
+			returnInterval, err = daterange.ParseDuration(*interval)
+			if err != nil {
+				log.Fatal(fmt.Errorf("invalid -interval: %v", err))
+			}
+		}
+		if *window != "" {
+			rollingWindow, err = daterange.ParseDuration(*window)
+			if err != nil {
+				log.Fatal(fmt.Errorf("invalid -window: %v", err))
+			}
+		}
 	}
 
-	return primaryColor
-}
-
-func main() {
-	var coin = flag.String("coin", "bitcoin", "Cryptocurrency name. ie. bitcoin | ethereum | litecoin | etc...")
-	var dateRange = flag.String("date", "7d", "Chart date range. ie. 1h | 1d | 2d | 7d | 30d | 2w | 1m | 3m | 1y")
-	var color = flag.String("color", "green", "Primary color. ie. green | cyan | magenta | red | yellow | white")
-	var lineChartHeight = flag.Uint("chart-height", 20, "Line chart height: .ie. 15 | 20 | 25 | 30")
-	var showTable = flag.Bool("table", false, "Show the top 50 cryptocurrencies in a table.")
-	var limit = flag.Uint("limit", 100, "Limit number of cryptocurrencies to return for table. ie. 10 | 25 | 50 | 100")
-	var refresh = flag.Uint("refresh", 60, "How often to refetch data in seconds: .ie. 30, 60")
-	var showGlobalMarketDash = flag.Bool("global", false, "Show global market data.")
+	if *showTable {
+		err = table.ValidateColumns(config.Columns)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	flag.Parse()
+	// the terminal views honor NO_COLOR, exported images keep their colors
+	t = t.FromEnv()
 
-	err := ui.Init()
+	err = initUI(t, depth)
 	if err != nil {
 		panic(err)
 	}
 	defer ui.Close()
 
-	if *refresh == 0 {
-		var i uint = 60
-		refresh = &i
-	}
-
-	if *showGlobalMarketDash {
-		err = RenderGlobalMarketDash(*color)
-	} else if *showTable {
+	if *showTable {
 		for {
-			err = RenderTable(*color, *limit, *refresh)
+			err = RenderTable(t, depth, *limit, *refresh, config.Columns, *loadAll, *capturePath)
 			if err != nil {
 				panic(err)
 			} else {
 				return
 			}
 		}
-	} else {
-		err = RenderChartDash(*coin, *dateRange, *color, *lineChartHeight)
 	}
 
+	render := func() error {
+		r, err := parseRange()
+		if err != nil {
+			return err
+		}
+		if *showGlobalMarketDash {
+			return dash.RenderGlobalMarket(r, *color, *lineChartHeight, dash.MoversOptions{
+				Count:     *moversCount,
+				Window:    *changeWindow,
+				Limit:     *limit,
+				MinCap:    *minCap,
+				MinVolume: *minVolume,
+			})
+		} else if *showHeatmap {
+			return dash.RenderHeatmap(*limit, *changeWindow, *color)
+		} else if showGrid {
+			return dash.RenderGrid(gridIDs, r, *limit, *color)
+		} else if showCorrelation {
+			return dash.RenderCorrelation(correlationIDs, r, returnInterval, rollingWindow, *limit, *color)
+		}
+		return dash.RenderChart(*coin, r, *color, *lineChartHeight)
+	}
+
+	err = render()
+
 	if err != nil {
 		panic(err)
 	}
 
+	if *capturePath != "" {
+		_, err := saveScreen(*capturePath)
+		if err != nil {
+			ui.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+
+	calc := newCalculator(*limit, *color)
+
+	if showChart {
+		enableMouse()
+		defer disableMouse()
+	}
+
+	// a click reports a press and a release at the same cell, while motion is
+	// only reported when the pointer moves to another cell
+	var lastMouse ui.EvtMouse
+	ui.Handle("/sys/mouse", func(e ui.Event) {
+		m := e.Data.(ui.EvtMouse)
+		click := m.X == lastMouse.X && m.Y == lastMouse.Y
+		lastMouse = m
+		if calc.Visible() {
+			return
+		}
+
+		// clicking a % change card switches the chart date range
+		if r, ok := dash.ChartRangeAt(m.X, m.Y); ok {
+			if click && (r != *dateRange || *fromDate != "") {
+				*dateRange = r
+				*fromDate, *toDate = "", ""
+				err := render()
+				if err != nil {
+					renderStatus(err.Error(), *color)
+				}
+			}
+			return
+		}
+		dash.ShowChartPoint(m.X, m.Y)
+	})
+
 	// re-adjust grid on window resize
 	ui.Handle("/sys/wnd/resize", func(ui.Event) {
 		ui.Body.Width = ui.TermWidth()
+		if showGrid {
+			dash.ArrangeGrid()
+		}
 		ui.Body.Align()
 		ui.Render(ui.Body)
+		calc.Render()
 	})
 
-	// quit on Ctrl-c
-	ui.Handle("/sys/kbd/C-c", func(ui.Event) {
-		ui.StopLoop()
-	})
+	ui.Handle("/sys/kbd", func(e ui.Event) {
+		key := e.Data.(ui.EvtKbd).KeyStr
 
-	// quit on q
-	ui.Handle("/sys/kbd/q", func(ui.Event) {
-		ui.StopLoop()
+		// quit on Ctrl-c
+		if key == "C-c" {
+			ui.StopLoop()
+			return
+		}
+
+		// the calculator takes the keys while it's open
+		if calc.Visible() {
+			if calc.HandleKey(key) {
+				if !calc.Visible() {
+					ui.Render(ui.Body)
+				}
+				calc.Render()
+			}
+			if key == "<enter>" {
+				go func() {
+					calc.Convert()
+					calc.Render()
+				}()
+			}
+			return
+		}
+
+		switch key {
+		case "q":
+			// quit on q
+			ui.StopLoop()
+		case "x":
+			// open the conversion calculator
+			calc.Open()
+			calc.Render()
+			err := calc.Refresh()
+			if err != nil {
+				renderStatus(err.Error(), *color)
+				return
+			}
+			calc.Render()
+		case "S":
+			// save a screen capture
+			path, err := saveScreen("")
+			if err != nil {
+				renderStatus(err.Error(), *color)
+				return
+			}
+			renderStatus("saved "+path, *color)
+		case "<left>", "<right>", "h", "l", "<home>", "<end>", "H", "L", "<escape>":
+			// move the chart cursor
+			if showChart {
+				dash.MoveChartCursor(key)
+			}
+		case "+", "=", "-", "[", "]":
+			// move the correlation window
+			if showCorrelation && (key == "[" || key == "]") {
+				dash.MoveCorrelationWindow(map[string]int{"[": -1, "]": 1}[key])
+				return
+			}
+			// zoom and pan the chart
+			if !showChart {
+				return
+			}
+			var err error
+			switch key {
+			case "+", "=":
+				err = dash.ZoomChart(1)
+			case "-":
+				err = dash.ZoomChart(-1)
+			case "[":
+				err = dash.PanChart(-1)
+			case "]":
+				err = dash.PanChart(1)
+			}
+			if err != nil {
+				renderStatus(err.Error(), *color)
+			}
+		case "v":
+			// switch the correlation between price returns and volume
+			if showCorrelation {
+				dash.ToggleCorrelationVolume()
+			}
+		case "1", "2", "7":
+			// switch the heatmap and top movers % change window
+			if *showHeatmap || *showGlobalMarketDash {
+				*changeWindow = map[string]string{"1": "1h", "2": "24h", "7": "7d"}[key]
+				err := render()
+				if err != nil {
+					renderStatus(err.Error(), *color)
+				}
+			}
+		}
 	})
 
-	// refresh every minute
-	ticker := time.NewTicker(time.Duration(int64(*refresh)) * time.Minute)
+	// refresh every -refresh seconds
+	ticker := time.NewTicker(time.Duration(int64(*refresh)) * time.Second)
 
 	// routine
 	go func() {
 	RESTART:
 		for range ticker.C {
-			var err error
-
-			if *showGlobalMarketDash {
-				err = RenderGlobalMarketDash(*color)
-			} else {
-				err = RenderChartDash(*coin, *dateRange, *color, *lineChartHeight)
-			}
+			err := render()
 
 			if err != nil {
+				renderStatus(err.Error(), *color)
 				goto RESTART
 			}
+
+			if calc.Visible() {
+				calc.Refresh()
+				calc.Render()
+			}
 		}
 	}()
 
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/miguelmota/cryptocharts/data"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// Daemon fetches coin and global market snapshots every refresh interval and
+// writes them to the configured sinks
+type Daemon struct {
+	limit   uint
+	refresh time.Duration
+	sinks   []*batchSink
+}
+
+// NewDaemon returns a new daemon writing the top limit coins to the sinks
+func NewDaemon(configs []SinkConfig, limit uint, refresh time.Duration) (*Daemon, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no sinks configured")
+	}
+
+	d := &Daemon{limit: limit, refresh: refresh}
+	for _, config := range configs {
+		sink, err := newBatchSink(config)
+		if err != nil {
+			return nil, err
+		}
+		d.sinks = append(d.sinks, sink)
+	}
+	return d, nil
+}
+
+// Run records a snapshot every refresh interval until the process exits
+func (d *Daemon) Run() error {
+	log.Printf("writing snapshots to %d sinks every %s", len(d.sinks), d.refresh)
+
+	ticker := time.NewTicker(d.refresh)
+	for {
+		err := d.record()
+		if err != nil {
+			log.Println(err)
+		}
+		<-ticker.C
+	}
+}
+
+// record fetches a snapshot and flushes it to every sink
+func (d *Daemon) record() error {
+	coins, err := data.FetchCoins(0, int(d.limit))
+	if err != nil {
+		return err
+	}
+
+	global, err := cmc.GetMarketData()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	points := []Point{globalPoint(global, now)}
+	for _, coin := range coins {
+		points = append(points, coinPoint(coin, now))
+	}
+
+	for _, sink := range d.sinks {
+		err := sink.Flush(points)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	return nil
+}
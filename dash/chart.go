@@ -0,0 +1,131 @@
+// Package dash renders the cryptocharts terminal dashboards with termui
+package dash
+
+import (
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// RenderChart renders the coin stats and price history chart dash
+func RenderChart(coin string, r daterange.Range, color string, lineChartHeight uint) error {
+	if coin == "" {
+		coin = "bitcoin"
+	}
+
+	primaryColor := widgets.Color(color)
+
+	coinInfo, err := cmc.GetCoinData(coin)
+
+	if err != nil {
+		return err
+	}
+
+	graphData, r, err := data.CoinHistory(coin, r)
+
+	if err != nil {
+		return err
+	}
+
+	if lineChartHeight == 0 {
+		lineChartHeight = 20
+	}
+
+	lastUpdated, err := format.LastUpdated(coinInfo.LastUpdated)
+
+	if err != nil {
+		return err
+	}
+
+	ath, first, err := allTime(coin, r, data.Stats(graphData.PriceUsd).High)
+
+	if err != nil {
+		return err
+	}
+
+	lc1 := widgets.NewPriceChart(coinInfo.Symbol, graphData, r, int(lineChartHeight), primaryColor)
+
+	// the % change cards switch the date range when clicked
+	cards := map[string]*ui.Par{
+		"1h": widgets.NewChangeCard("% Change (1H)", coinInfo.PercentChange1h),
+		"1d": widgets.NewChangeCard("% Change (24H)", coinInfo.PercentChange24h),
+		"7d": widgets.NewChangeCard("% Change (7D)", coinInfo.PercentChange7d),
+	}
+
+	stats := newRangeCards(primaryColor)
+
+	// reset
+	ui.Body.Rows = ui.Body.Rows[:0]
+
+	// add grid rows and columns
+	ui.Body.AddRows(
+		ui.NewRow(
+			ui.NewCol(2, 0, widgets.NewStatCard("Name", coinInfo.Name, primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Symbol", coinInfo.Symbol, primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Price (USD)", format.USD(coinInfo.PriceUsd), primaryColor)),
+			ui.NewCol(2, 0, cards["1h"]),
+			ui.NewCol(2, 0, cards["1d"]),
+			ui.NewCol(2, 0, cards["7d"]),
+		),
+		ui.NewRow(
+			ui.NewCol(2, 0, widgets.NewStatCard("Rank", format.Count(coinInfo.Rank), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Market Cap", format.USD(coinInfo.MarketCapUsd), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Volume (24H)", format.USD(coinInfo.Usd24hVolume), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Circulating Supply", format.Amount(coinInfo.AvailableSupply, coinInfo.Symbol), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Total Supply", format.Amount(coinInfo.TotalSupply, coinInfo.Symbol), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Last Updated", lastUpdated, primaryColor)),
+		),
+		stats.row(),
+		ui.NewRow(
+			ui.NewCol(12, 0, lc1),
+		),
+	)
+
+	err = setChartView(lc1, cards, stats, coinInfo, r, graphData, ath, first)
+
+	if err != nil {
+		return err
+	}
+
+	// calculate layout
+	ui.Body.Align()
+
+	// render to terminal
+	ui.Render(ui.Body)
+
+	return nil
+}
+
+// allTime returns the coin's all time high and the unix timestamp its
+// history starts at, fetched once per coin. The high is raised when the
+// range's high is higher. all ranges run through both, so they're taken from
+// the range without fetching
+func allTime(coin string, r daterange.Range, high float64) (float64, int64, error) {
+	view.Lock()
+	var ath float64
+	var first int64
+	if view.coin.ID == coin {
+		ath, first = view.ath, view.first
+	}
+	view.Unlock()
+
+	if ath == 0 {
+		if r.Name == daterange.All {
+			first = r.Start
+		} else {
+			var err error
+			ath, first, err = data.AllTimeHigh(coin)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	if high > ath {
+		ath = high
+	}
+	return ath, first, nil
+}
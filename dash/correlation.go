@@ -0,0 +1,238 @@
+package dash
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/widgets"
+)
+
+// correlation is the last rendered correlation dash, kept for key events
+var correlation struct {
+	sync.Mutex
+	matrix   *widgets.Matrix
+	coins    []string
+	rng      daterange.Range
+	start    int64       // unix timestamp of the first aligned value
+	interval int64       // seconds between aligned values
+	window   int         // returns per window, 0 for the whole range
+	prices   [][]float64 // price returns by coin
+	volumes  [][]float64 // volume changes by coin
+	offset   int         // steps back from the latest window
+	volume   bool        // correlate volume changes instead of price returns
+}
+
+// RenderCorrelation renders a matrix of the Pearson correlation between the
+// coins' returns at the interval over the date range, or over a rolling
+// window of the range. An interval of 0 uses the coarsest resolution of the
+// coins' histories. The coins are fetched together, among the top limit coins
+func RenderCorrelation(coins []string, r daterange.Range, interval, window int64, limit uint, color string) error {
+	primaryColor := widgets.Color(color)
+
+	coinsData, err := data.FetchCoinsByID(coins, int(limit))
+
+	if err != nil {
+		return err
+	}
+
+	graphs, err := data.FetchHistories(coins, r)
+
+	if err != nil {
+		return err
+	}
+
+	// the start of all and since-ath ranges is the earliest history
+	start := r.Start
+	for _, graph := range graphs {
+		if len(graph.PriceUsd) == 0 {
+			continue
+		}
+		if first := int64(graph.PriceUsd[0][0]) / 1000; start == 0 || (r.Start == 0 && first < start) {
+			start = first
+		}
+	}
+
+	if interval == 0 {
+		for _, graph := range graphs {
+			if resolution := data.Resolution(graph.PriceUsd); resolution > interval {
+				interval = resolution
+			}
+		}
+	}
+	if interval == 0 {
+		return fmt.Errorf("not enough price history to correlate over %s", r.Label())
+	}
+
+	returns := 0
+	if window > 0 {
+		returns = int(window / interval)
+		if returns < 3 {
+			return fmt.Errorf("the correlation window must be at least 3 intervals of %s", intervalLabel(interval))
+		}
+	}
+
+	symbols := make([]string, len(coinsData))
+	prices := make([][]float64, len(graphs))
+	volumes := make([][]float64, len(graphs))
+	for i, graph := range graphs {
+		symbols[i] = coinsData[i].Symbol
+		prices[i] = data.Returns(data.Align(graph.PriceUsd, start, r.End, interval))
+		volumes[i] = data.Returns(data.Align(graph.VolumeUsd, start, r.End, interval))
+	}
+
+	matrix := widgets.NewMatrix()
+	matrix.Labels = symbols
+	matrix.Height = ui.TermHeight()
+	matrix.BorderFg = widgets.BorderColor()
+	matrix.BorderLabelFg = primaryColor
+
+	correlation.Lock()
+	defer correlation.Unlock()
+
+	// keep the window and volume switch when refreshing the same coins
+	if strings.Join(correlation.coins, ",") != strings.Join(coins, ",") || correlation.window != returns {
+		correlation.offset = 0
+		correlation.volume = false
+	}
+	correlation.matrix = matrix
+	correlation.coins = coins
+	correlation.rng = r
+	correlation.start = start
+	correlation.interval = interval
+	correlation.window = returns
+	correlation.prices = prices
+	correlation.volumes = volumes
+
+	// reset
+	ui.Body.Rows = ui.Body.Rows[:0]
+
+	// add grid rows and columns
+	ui.Body.AddRows(
+		ui.NewRow(
+			ui.NewCol(12, 0, matrix),
+		),
+	)
+
+	// calculate layout
+	ui.Body.Align()
+
+	renderCorrelation()
+
+	return nil
+}
+
+// ToggleCorrelationVolume switches the correlation dash between price
+// returns and volume changes
+func ToggleCorrelationVolume() {
+	correlation.Lock()
+	defer correlation.Unlock()
+	if correlation.matrix == nil {
+		return
+	}
+	correlation.volume = !correlation.volume
+	renderCorrelation()
+}
+
+// MoveCorrelationWindow moves the correlation dash's rolling window by a
+// quarter of its length, to earlier times for a negative dir
+func MoveCorrelationWindow(dir int) {
+	correlation.Lock()
+	defer correlation.Unlock()
+	if correlation.matrix == nil || correlation.window == 0 {
+		return
+	}
+	correlation.offset -= dir
+	if correlation.offset < 0 {
+		correlation.offset = 0
+	}
+	renderCorrelation()
+}
+
+// renderCorrelation renders the correlation matrix of the current window
+func renderCorrelation() {
+	series := correlation.prices
+	kind := "Price Returns"
+	if correlation.volume {
+		series = correlation.volumes
+		kind = "Volume Changes"
+	}
+
+	label := fmt.Sprintf("Correlation of %s (%s intervals): %s", kind, intervalLabel(correlation.interval), correlation.rng.Label())
+	footer := "v: switch to volume"
+	if correlation.volume {
+		footer = "v: switch to price"
+	}
+
+	if n := correlation.window; n > 0 && len(series) > 0 {
+		// windows step back by a quarter of their length from the latest
+		step := n / 4
+		if step < 1 {
+			step = 1
+		}
+		total := len(series[0])
+		last := (total - n + step - 1) / step
+		if last < 0 {
+			last = 0
+		}
+		if correlation.offset > last {
+			correlation.offset = last
+		}
+		end := total - correlation.offset*step
+		start := end - n
+		if start < 0 {
+			start, end = 0, n
+		}
+		if end > total {
+			end = total
+		}
+
+		windowed := make([][]float64, len(series))
+		for i := range series {
+			windowed[i] = series[i][start:end]
+		}
+		series = windowed
+
+		// return i is from aligned value i to i+1
+		bounds := daterange.Range{
+			Start: correlation.start + int64(start)*correlation.interval,
+			End:   correlation.start + int64(end)*correlation.interval,
+		}
+		label += fmt.Sprintf("  Window: %s", bounds.Label())
+		footer += "  [ ]: move window"
+	}
+
+	correlation.matrix.BorderLabel = label
+	correlation.matrix.Footer = footer
+	correlation.matrix.Values = data.CorrelationMatrix(series)
+	ui.Render(correlation.matrix)
+}
+
+// intervalLabel formats an interval in seconds with spelled out minutes, as
+// m is months in date ranges. ie. 5min | 1h | 1d12h
+func intervalLabel(interval int64) string {
+	units := []struct {
+		secs int64
+		name string
+	}{
+		{daterange.OneDay, "d"},
+		{daterange.OneHour, "h"},
+		{daterange.OneMinute, "min"},
+		{1, "s"},
+	}
+
+	label := ""
+	for _, unit := range units {
+		if n := interval / unit.secs; n > 0 {
+			label += fmt.Sprintf("%d%s", n, unit.name)
+			interval -= n * unit.secs
+		}
+	}
+	if label == "" {
+		return "0s"
+	}
+	return label
+}
@@ -0,0 +1,71 @@
+package dash
+
+import (
+	"fmt"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/format"
+)
+
+// MoveChartCursor moves the chart dash cursor for the key and shows the
+// point under it in the chart's label. ie. <left> | <right> | h | l |
+// <home> | <end> | H for the high | L for the low | <escape> to hide it
+func MoveChartCursor(key string) {
+	view.Lock()
+	defer view.Unlock()
+	chart := view.chart
+	if chart == nil || len(chart.Series) == 0 {
+		return
+	}
+
+	switch key {
+	case "<left>", "h":
+		chart.MoveCursor(-1)
+	case "<right>", "l":
+		chart.MoveCursor(1)
+	case "<home>":
+		chart.Cursor = 0
+	case "<end>":
+		chart.Cursor = len(chart.Series) - 1
+	case "H":
+		chart.SnapCursor(true)
+	case "L":
+		chart.SnapCursor(false)
+	case "<escape>":
+		chart.Cursor = -1
+	default:
+		return
+	}
+	renderCursor()
+}
+
+// renderCursor re-renders the chart with the cursor's readout in its label
+func renderCursor() {
+	view.chart.BorderLabel = cursorLabel()
+	ui.Render(view.chart)
+}
+
+// cursorLabel returns the chart label with the time, price and volume of the
+// point under the cursor, and its % change from the start of the range and
+// from the previous point
+func cursorLabel() string {
+	i := view.chart.Cursor
+	prices := view.shown.PriceUsd
+	if i < 0 || i >= len(prices) {
+		return view.label
+	}
+
+	point := prices[i]
+	label := fmt.Sprintf("%s  %s  %s", view.label, format.Time(int64(point[0])/1000), format.USD(point[1]))
+	if i < len(view.shown.VolumeUsd) {
+		label += fmt.Sprintf("  Vol %s", format.USD(view.shown.VolumeUsd[i][1]))
+	}
+	if start := prices[0][1]; start != 0 {
+		label += fmt.Sprintf("  %s from start", format.SignedPercent((point[1]-start)/start*100))
+	}
+	if i > 0 && prices[i-1][1] != 0 {
+		prev := prices[i-1][1]
+		label += fmt.Sprintf("  %s from prev", format.SignedPercent((point[1]-prev)/prev*100))
+	}
+	return label
+}
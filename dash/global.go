@@ -0,0 +1,105 @@
+package dash
+
+import (
+	"fmt"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// MoversOptions configures the top gainers and losers panels
+type MoversOptions struct {
+	Count     uint    // number of coins per panel, 0 hides the panels
+	Window    string  // % change window. ie. 1h | 24h | 7d
+	Limit     uint    // number of top coins to pick from
+	MinCap    float64 // minimum market cap in USD
+	MinVolume float64 // minimum 24 hour volume in USD
+}
+
+// RenderGlobalMarket renders the global market dash with history charts over
+// the date range and the top gainers and losers
+func RenderGlobalMarket(r daterange.Range, color string, lineChartHeight uint, movers MoversOptions) error {
+	primaryColor := widgets.Color(color)
+
+	marketData, err := cmc.GetMarketData()
+
+	if err != nil {
+		return err
+	}
+
+	marketGraph, dominanceGraph, r, err := data.GlobalHistory(r)
+
+	if err != nil {
+		return err
+	}
+
+	var gainers, losers []cmc.Coin
+	if movers.Count > 0 {
+		err = data.ValidateChangeWindow(movers.Window)
+
+		if err != nil {
+			return err
+		}
+
+		coinsMap, err := cmc.GetAllCoinData(int(movers.Limit))
+
+		if err != nil {
+			return err
+		}
+
+		gainers, losers = data.TopMovers(data.SortByMarketCap(coinsMap), movers.Window, int(movers.Count), movers.MinCap, movers.MinVolume)
+	}
+
+	if lineChartHeight == 0 {
+		lineChartHeight = 20
+	}
+
+	height := int(lineChartHeight)
+
+	lc0 := widgets.NewSeriesChart(fmt.Sprintf("%s: %s", "Total Market Cap History", r.Label()), marketGraph.MarketCapUsd, height, primaryColor)
+	lc1 := widgets.NewSeriesChart(fmt.Sprintf("%s: %s", "Total Volume (24H) History", r.Label()), marketGraph.VolumeUsd, height, primaryColor)
+	lc2 := widgets.NewSeriesChart(fmt.Sprintf("%s: %s", "% Bitcoin Dominance History", r.Label()), dominanceGraph.Bitcoin, height, primaryColor)
+
+	// reset
+	ui.Body.Rows = ui.Body.Rows[:0]
+
+	// add grid rows and columns
+	ui.Body.AddRows(
+		ui.NewRow(
+			ui.NewCol(2, 0, widgets.NewStatCard("Total Market Cap (USD)", format.USD(marketData.TotalMarketCapUsd), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Total Volume (24H)", format.USD(marketData.Total24hVolumeUsd), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("% Bitcoin Dominance", format.Percent(marketData.BitcoinPercentageOfMarketCap), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Active Currencies", format.Count(marketData.ActiveCurrencies), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Active Assets", format.Count(marketData.ActiveAssets), primaryColor)),
+			ui.NewCol(2, 0, widgets.NewStatCard("Active Markets", format.Count(marketData.ActiveMarkets), primaryColor)),
+		),
+		ui.NewRow(
+			ui.NewCol(12, 0, lc0),
+		),
+		ui.NewRow(
+			ui.NewCol(6, 0, lc1),
+			ui.NewCol(6, 0, lc2),
+		),
+	)
+
+	if movers.Count > 0 {
+		ui.Body.AddRows(
+			ui.NewRow(
+				ui.NewCol(6, 0, widgets.NewMoversList("Top Gainers", gainers, movers.Window, int(movers.Count), primaryColor)),
+				ui.NewCol(6, 0, widgets.NewMoversList("Top Losers", losers, movers.Window, int(movers.Count), primaryColor)),
+			),
+		)
+	}
+
+	// calculate layout
+	ui.Body.Align()
+
+	// render to terminal
+	ui.Render(ui.Body)
+
+	return nil
+}
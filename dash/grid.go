@@ -0,0 +1,115 @@
+package dash
+
+import (
+	"fmt"
+	"sync"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+)
+
+// grid panel minimum sizes in cells
+const (
+	gridPanelWidth  = 32
+	gridPanelHeight = 8
+)
+
+// gridColumns are the numbers of columns that divide the 12 span termui grid
+// evenly
+var gridColumns = []int{1, 2, 3, 4, 6}
+
+// grid is the last rendered grid dash, kept to arrange on resize
+var grid struct {
+	sync.Mutex
+	panels []*widgets.SeriesChart
+}
+
+// RenderGrid renders a panel for each coin with its price, 24 hour % change
+// and a mini price chart over the date range. The coins are fetched
+// together, among the top limit coins, and the panels fill the terminal
+func RenderGrid(coins []string, r daterange.Range, limit uint, color string) error {
+	primaryColor := widgets.Color(color)
+
+	coinsData, err := data.FetchCoinsByID(coins, int(limit))
+
+	if err != nil {
+		return err
+	}
+
+	graphs, err := data.FetchHistories(coins, r)
+
+	if err != nil {
+		return err
+	}
+
+	panels := make([]*widgets.SeriesChart, len(coinsData))
+	for i, coin := range coinsData {
+		label := fmt.Sprintf("%s %s %s", coin.Symbol, format.USD(coin.PriceUsd), format.SignedPercent(coin.PercentChange24h))
+		panels[i] = widgets.NewSeriesChart(label, graphs[i].PriceUsd, gridPanelHeight, primaryColor)
+		panels[i].BorderLabelFg = widgets.ChangeColor(coin.PercentChange24h)
+	}
+
+	grid.Lock()
+	grid.panels = panels
+	grid.Unlock()
+
+	ArrangeGrid()
+
+	// render to terminal
+	ui.Render(ui.Body)
+
+	return nil
+}
+
+// ArrangeGrid lays the grid dash panels out in as few rows as fit the
+// terminal's width, and stretches them to fill its height
+func ArrangeGrid() {
+	grid.Lock()
+	defer grid.Unlock()
+	n := len(grid.panels)
+	if n == 0 {
+		return
+	}
+
+	columns := 1
+	for _, c := range gridColumns {
+		if ui.TermWidth()/c >= gridPanelWidth && (c == 1 || c <= n) {
+			columns = c
+		}
+	}
+	rows := (n + columns - 1) / columns
+
+	// balance the rows, with the fewest columns that take as many rows
+	for _, c := range gridColumns {
+		if c*rows >= n {
+			columns = c
+			break
+		}
+	}
+	height := ui.TermHeight() / rows
+	if height < gridPanelHeight {
+		height = gridPanelHeight
+	}
+
+	// reset
+	ui.Body.Rows = ui.Body.Rows[:0]
+
+	for i := 0; i < n; i += columns {
+		end := i + columns
+		if end > n {
+			end = n
+		}
+		var cols []*ui.Row
+		for _, panel := range grid.panels[i:end] {
+			panel.Height = height
+			cols = append(cols, ui.NewCol(12/columns, 0, panel))
+		}
+		ui.Body.AddRows(ui.NewRow(cols...))
+	}
+
+	// calculate layout
+	ui.Body.Align()
+}
@@ -0,0 +1,101 @@
+package dash
+
+import (
+	"fmt"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// RenderHeatmap renders a treemap of the top coins sized by market cap and
+// colored by % change over the window, with the market share of the largest
+// coins
+func RenderHeatmap(limit uint, window string, color string) error {
+	primaryColor := widgets.Color(color)
+
+	coinsMap, err := cmc.GetAllCoinData(int(limit))
+
+	if err != nil {
+		return err
+	}
+
+	marketData, err := cmc.GetMarketData()
+
+	if err != nil {
+		return err
+	}
+
+	coins := data.SortByMarketCap(coinsMap)
+
+	height := ui.TermHeight()
+
+	treemap := widgets.NewTreemap()
+	treemap.Height = height
+	treemap.BorderFg = widgets.BorderColor()
+	treemap.BorderLabel = fmt.Sprintf("Market Cap Heatmap: %% Change (%s)", window)
+	treemap.BorderLabelFg = primaryColor
+	for _, coin := range coins {
+		change := data.PercentChange(coin, window)
+		treemap.Tiles = append(treemap.Tiles, widgets.TreemapTile{
+			Label:    coin.Symbol,
+			Sublabel: format.Percent(change),
+			Value:    coin.MarketCapUsd,
+			Fg:       widgets.TileTextColor(),
+			Bg:       widgets.ChangeColor(change),
+		})
+	}
+
+	total := marketData.TotalMarketCapUsd
+	shares := widgets.NewShareBars()
+	shares.Height = height
+	shares.BarColor = primaryColor
+	shares.BorderFg = widgets.BorderColor()
+	shares.BorderLabel = "% Market Share"
+	shares.BorderLabelFg = primaryColor
+
+	// one bar per row, keeping a row for others
+	n := height - 3
+	if n > len(coins) {
+		n = len(coins)
+	}
+	if n < 0 {
+		n = 0
+	}
+	others := 100.0
+	for _, coin := range coins[:n] {
+		share := 0.0
+		if total > 0 {
+			share = coin.MarketCapUsd / total * 100
+		}
+		shares.Labels = append(shares.Labels, coin.Symbol)
+		shares.Percents = append(shares.Percents, share)
+		others -= share
+	}
+	if others < 0 {
+		others = 0
+	}
+	shares.Labels = append(shares.Labels, "Others")
+	shares.Percents = append(shares.Percents, others)
+
+	// reset
+	ui.Body.Rows = ui.Body.Rows[:0]
+
+	// add grid rows and columns
+	ui.Body.AddRows(
+		ui.NewRow(
+			ui.NewCol(9, 0, treemap),
+			ui.NewCol(3, 0, shares),
+		),
+	)
+
+	// calculate layout
+	ui.Body.Align()
+
+	// render to terminal
+	ui.Render(ui.Body)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package dash
+
+import (
+	"image"
+	"sync"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/widgets"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// chartView is the last rendered chart dash, kept for mouse, cursor and
+// zoom events
+type chartView struct {
+	sync.Mutex
+	chart   *widgets.SeriesChart
+	label   string
+	coin    cmc.Coin
+	ath     float64            // all time high price
+	first   int64              // unix timestamp the coin's history starts at
+	rng     daterange.Range    // date range of the dash
+	graph   cmc.CoinGraph      // fetched history
+	fetched daterange.Range    // period of the fetched history
+	visible daterange.Range    // zoomed period shown in the chart
+	shown   cmc.CoinGraph      // history in the visible period
+	cards   map[string]*ui.Par // % change cards by date range
+	stats   rangeCards
+}
+
+var view chartView
+
+// setChartView keeps the chart and cards for mouse, cursor and zoom events.
+// The zoom and cursor are kept when the chart is refreshed with the same
+// date range, and an error is returned when the range can't be shown
+func setChartView(chart *widgets.SeriesChart, cards map[string]*ui.Par, stats rangeCards, coin cmc.Coin, r daterange.Range, graph cmc.CoinGraph, ath float64, first int64) error {
+	view.Lock()
+	defer view.Unlock()
+
+	visible, cursor := r, -1.0
+	if prev := view.chart; prev != nil && view.coin.ID == coin.ID && view.rng.Name == r.Name && (r.Name != "" || view.rng == r) {
+		visible = view.visible
+		if prev.Cursor >= 0 {
+			cursor = prev.Series[prev.Cursor][0]
+		}
+	}
+
+	view.chart = chart
+	view.coin = coin
+	view.ath = ath
+	view.first = first
+	view.rng = r
+	view.graph = graph
+	view.fetched = r
+	view.cards = cards
+	view.stats = stats
+	err := showRange(visible)
+	if err != nil && visible != r {
+		err = showRange(r)
+	}
+	if err != nil {
+		return err
+	}
+	restoreCursor(cursor)
+	return nil
+}
+
+// restoreCursor moves the cursor to the timestamp in ms when it's on the
+// chart
+func restoreCursor(t float64) {
+	series := view.chart.Series
+	if t >= 0 && len(series) > 0 && t >= series[0][0] && t <= series[len(series)-1][0] {
+		view.chart.SetCursorTime(t)
+	}
+	view.chart.BorderLabel = cursorLabel()
+}
+
+// ChartRangeAt returns the date range of the % change card at the screen
+// point of the chart dash. ie. 1h | 1d | 7d
+func ChartRangeAt(x, y int) (string, bool) {
+	view.Lock()
+	defer view.Unlock()
+	for dateRange, card := range view.cards {
+		bounds := image.Rect(card.X, card.Y, card.X+card.Width, card.Y+card.Height)
+		if image.Pt(x, y).In(bounds) {
+			return dateRange, true
+		}
+	}
+	return "", false
+}
+
+// ShowChartPoint moves the chart cursor to the point under the screen point
+func ShowChartPoint(x, y int) {
+	view.Lock()
+	defer view.Unlock()
+	if view.chart == nil || !view.chart.CursorAt(x, y) {
+		return
+	}
+	renderCursor()
+}
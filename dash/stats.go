@@ -0,0 +1,64 @@
+package dash
+
+import (
+	"fmt"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/format"
+	"github.com/miguelmota/cryptocharts/widgets"
+)
+
+// rangeCards are the stat cards of the price history in the chart
+type rangeCards struct {
+	high       *ui.Par
+	low        *ui.Par
+	change     *ui.Par
+	drawdown   *ui.Par
+	volatility *ui.Par
+	fromATH    *ui.Par
+}
+
+// newRangeCards returns the range stat cards
+func newRangeCards(primaryColor ui.Attribute) rangeCards {
+	return rangeCards{
+		high:       widgets.NewStatCard("High (Range)", "", primaryColor),
+		low:        widgets.NewStatCard("Low (Range)", "", primaryColor),
+		change:     widgets.NewChangeCard("% Change (Range)", 0),
+		drawdown:   widgets.NewStatCard("Max Drawdown", "", primaryColor),
+		volatility: widgets.NewStatCard("Volatility (Annualized)", "", primaryColor),
+		fromATH:    widgets.NewChangeCard("% From ATH", 0),
+	}
+}
+
+// row returns the grid row of the cards
+func (c rangeCards) row() *ui.Row {
+	return ui.NewRow(
+		ui.NewCol(2, 0, c.high),
+		ui.NewCol(2, 0, c.low),
+		ui.NewCol(2, 0, c.change),
+		ui.NewCol(2, 0, c.drawdown),
+		ui.NewCol(2, 0, c.volatility),
+		ui.NewCol(2, 0, c.fromATH),
+	)
+}
+
+// update sets the cards to the stats of the price series, and the distance
+// of the price from the all time high
+func (c rangeCards) update(series [][]float64, price float64, ath float64) {
+	stats := data.Stats(series)
+	c.high.Text = fmt.Sprintf("%s %s", format.USD(stats.High), format.Time(int64(stats.HighTime)/1000))
+	c.low.Text = fmt.Sprintf("%s %s", format.USD(stats.Low), format.Time(int64(stats.LowTime)/1000))
+	widgets.SetChange(c.change, stats.Change)
+	c.drawdown.Text = format.Percent(-stats.MaxDrawdown)
+	c.volatility.Text = format.Percent(stats.Volatility)
+	if ath < stats.High {
+		ath = stats.High
+	}
+	widgets.SetChange(c.fromATH, data.FromHigh(price, ath))
+}
+
+// render renders the cards
+func (c rangeCards) render() {
+	ui.Render(c.high, c.low, c.change, c.drawdown, c.volatility, c.fromATH)
+}
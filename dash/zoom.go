@@ -0,0 +1,117 @@
+package dash
+
+import (
+	"fmt"
+	"time"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/widgets"
+)
+
+// minZoomSpan is the shortest period the chart can be zoomed in to
+const minZoomSpan = 10 * 60
+
+// ZoomChart zooms the chart dash in by half around the cursor, or around
+// the middle of the chart without one, or out by double for a negative dir
+// until the coin's whole history is shown
+func ZoomChart(dir int) error {
+	view.Lock()
+	defer view.Unlock()
+	if view.chart == nil {
+		return nil
+	}
+
+	v := view.visible
+	anchor := v.Start + (v.End-v.Start)/2
+	if i := view.chart.Cursor; i >= 0 {
+		anchor = int64(view.chart.Series[i][0]) / 1000
+	}
+
+	start, span := anchor-(anchor-v.Start)*2, (v.End-v.Start)*2
+	if dir > 0 {
+		start, span = anchor-(anchor-v.Start)/2, (v.End-v.Start)/2
+	}
+	if span < minZoomSpan {
+		return nil
+	}
+	return moveVisible(start, span)
+}
+
+// PanChart pans the chart dash by a quarter of its period, to earlier times
+// for a negative dir
+func PanChart(dir int) error {
+	view.Lock()
+	defer view.Unlock()
+	if view.chart == nil {
+		return nil
+	}
+
+	v := view.visible
+	span := v.End - v.Start
+	return moveVisible(v.Start+int64(dir)*span/4, span)
+}
+
+// moveVisible shows the period of span seconds from start in the chart,
+// kept within the coin's history, and keeps the cursor at its time
+func moveVisible(start, span int64) error {
+	now := time.Now().Unix()
+	if span > now-view.first {
+		span = now - view.first
+	}
+	if start+span > now {
+		start = now - span
+	}
+	if start < view.first {
+		start = view.first
+	}
+	if start == view.visible.Start && start+span == view.visible.End {
+		return nil
+	}
+
+	cursor := -1.0
+	if i := view.chart.Cursor; i >= 0 {
+		cursor = view.chart.Series[i][0]
+	}
+	err := showRange(daterange.Range{Start: start, End: start + span})
+	if err != nil {
+		return err
+	}
+	restoreCursor(cursor)
+	ui.Render(view.chart)
+	view.stats.render()
+	return nil
+}
+
+// showRange shows the period in the chart, fetching the history again when
+// the period runs past the fetched history, or when there are fewer points
+// in it than columns in the chart and a shorter fetch returns finer points
+func showRange(v daterange.Range) error {
+	shown := data.GraphBetween(view.graph, v.Start, v.End)
+	columns := len(view.chart.Samples) / 2
+	if columns == 0 {
+		columns = view.chart.Width
+	}
+	outside := v.Start < view.fetched.Start || v.End > view.fetched.End
+	coarse := len(shown.PriceUsd) < columns && v.End-v.Start < view.fetched.End-view.fetched.Start
+	if outside || coarse {
+		graph, _, err := data.CoinHistory(view.coin.ID, v)
+		if err != nil {
+			return err
+		}
+		view.graph, view.fetched = graph, v
+		shown = data.GraphBetween(graph, v.Start, v.End)
+	}
+	if len(shown.PriceUsd) < 2 {
+		return fmt.Errorf("no price history from %s", v.Label())
+	}
+
+	view.visible = v
+	view.shown = shown
+	view.label = widgets.PriceChartLabel(view.coin.Symbol, v)
+	view.chart.SetSeries(shown.PriceUsd)
+	view.chart.BorderLabel = view.label
+	view.stats.update(shown.PriceUsd, view.coin.PriceUsd, view.ath)
+	return nil
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/miguelmota/cryptocharts/daterange"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// FetchCoinsByID fetches the coins with the ids in order, in one request for
+// the coins among the top limit and one request each for the rest
+func FetchCoinsByID(ids []string, limit int) ([]cmc.Coin, error) {
+	top, err := FetchCoins(0, limit)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]cmc.Coin{}
+	for _, coin := range top {
+		byID[coin.ID] = coin
+	}
+
+	coins := make([]cmc.Coin, len(ids))
+	for i, id := range ids {
+		coin, ok := byID[id]
+		if !ok {
+			coin, err = cmc.GetCoinData(id)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", id, err)
+			}
+		}
+		coins[i] = coin
+	}
+	return coins, nil
+}
+
+// FetchHistories fetches the coins' history over the date range in order,
+// all at once
+func FetchHistories(ids []string, r daterange.Range) ([]cmc.CoinGraph, error) {
+	graphs := make([]cmc.CoinGraph, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			graphs[i], _, errs[i] = CoinHistory(id, r)
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ids[i], err)
+		}
+	}
+	return graphs, nil
+}
@@ -0,0 +1,24 @@
+// Package data fetches coin and market data from CoinMarketCap
+package data
+
+import (
+	"fmt"
+
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+var tickerURL = "https://api.coinmarketcap.com/v1/ticker/"
+
+// FetchCoins fetches a page of coins ordered by rank starting at the start
+// offset, a limit of 0 fetches all remaining coins
+func FetchCoins(start, limit int) ([]cmc.Coin, error) {
+	url := fmt.Sprintf("%s?start=%d&limit=%d", tickerURL, start, limit)
+
+	var coins []cmc.Coin
+	err := getJSON(url, &coins)
+	if err != nil {
+		return nil, err
+	}
+
+	return coins, nil
+}
@@ -0,0 +1,267 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/miguelmota/cryptocharts/format"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// FiatCurrencies are the fiat currencies prices can be converted to
+var FiatCurrencies = []string{
+	"USD", "AUD", "BRL", "CAD", "CHF", "CLP", "CNY", "CZK", "DKK", "EUR", "GBP",
+	"HKD", "HUF", "IDR", "ILS", "INR", "JPY", "KRW", "MXN", "MYR", "NOK", "NZD",
+	"PHP", "PKR", "PLN", "RUB", "SEK", "SGD", "THB", "TRY", "TWD", "ZAR",
+}
+
+// IsFiat returns true if the currency code is a supported fiat currency
+func IsFiat(currency string) bool {
+	currency = strings.ToUpper(currency)
+	for _, fiat := range FiatCurrencies {
+		if fiat == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchFiatRate fetches the units of the fiat currency per US dollar
+func FetchFiatRate(currency string) (float64, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "USD" {
+		return 1, nil
+	}
+
+	url := fmt.Sprintf("%sbitcoin/?convert=%s", tickerURL, currency)
+
+	var coins []map[string]interface{}
+	err := getJSON(url, &coins)
+	if err != nil {
+		return 0, err
+	}
+	if len(coins) == 0 {
+		return 0, fmt.Errorf("no rate for %s", currency)
+	}
+
+	usd, err := strconv.ParseFloat(fmt.Sprint(coins[0]["price_usd"]), 64)
+	if err != nil {
+		return 0, err
+	}
+	price, err := strconv.ParseFloat(fmt.Sprint(coins[0]["price_"+strings.ToLower(currency)]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("no rate for %s", currency)
+	}
+	if usd == 0 {
+		return 0, fmt.Errorf("no rate for %s", currency)
+	}
+
+	return price / usd, nil
+}
+
+// Conversion is a conversion query. ie. 1.5 btc eth
+type Conversion struct {
+	Amount float64
+	From   string
+	To     string
+}
+
+// ParseConversion parses the amount, from and to arguments of a conversion
+func ParseConversion(args []string) (Conversion, error) {
+	if len(args) != 3 {
+		return Conversion{}, fmt.Errorf("expected <amount> <from> <to>. ie. 1.5 btc eth")
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(args[0], ",", "", -1), 64)
+	if err != nil {
+		return Conversion{}, fmt.Errorf("invalid amount %q", args[0])
+	}
+
+	return Conversion{Amount: amount, From: args[1], To: args[2]}, nil
+}
+
+// Converter converts amounts between coins and fiat currencies by their USD
+// prices, resolving coins by id, symbol or name
+type Converter struct {
+	mu       sync.Mutex
+	coins    map[string]cmc.Coin
+	rates    map[string]float64
+	resolver *Resolver
+}
+
+// NewConverter returns a converter pricing coins from the given coins, and
+// fetching any other coin it resolves
+func NewConverter(coins []cmc.Coin) *Converter {
+	c := &Converter{
+		coins:    map[string]cmc.Coin{},
+		rates:    map[string]float64{"USD": 1},
+		resolver: defaultResolver,
+	}
+	for _, coin := range coins {
+		c.coins[coin.ID] = coin
+	}
+	return c
+}
+
+// Convert converts the amount of the from coin or currency to the to coin or
+// currency, fetching fiat rates as needed. An *AmbiguousError is returned
+// when a coin matches more than one listed coin
+func (c *Converter) Convert(amount float64, from, to string) (float64, error) {
+	fromPrice, err := c.usdPrice(from)
+	if err != nil {
+		return 0, err
+	}
+	toPrice, err := c.usdPrice(to)
+	if err != nil {
+		return 0, err
+	}
+	if toPrice == 0 {
+		return 0, fmt.Errorf("no price for %s", c.Symbol(to))
+	}
+
+	return amount * fromPrice / toPrice, nil
+}
+
+// ConvertInput converts a typed input such as 1.5 btc eth, returning the
+// result formatted with the symbol it was converted to. Coin names with
+// spaces are quoted. ie. 1 "bitcoin cash" usd
+func (c *Converter) ConvertInput(input string) (string, error) {
+	conversion, err := ParseConversion(splitInput(input))
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.Convert(conversion.Amount, conversion.From, conversion.To)
+	if err != nil {
+		return "", err
+	}
+
+	return format.Amount(Round(result), c.Symbol(conversion.To)), nil
+}
+
+// splitInput splits an input on spaces outside of double quotes
+func splitInput(input string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted, started := false, false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case r == ' ' && !quoted:
+			if started {
+				fields = append(fields, field.String())
+				field.Reset()
+				started = false
+			}
+		default:
+			field.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// Symbol returns the display symbol of a coin or currency. ie. BTC for bitcoin
+func (c *Converter) Symbol(unit string) string {
+	if IsFiat(unit) {
+		return strings.ToUpper(unit)
+	}
+	listing, err := c.resolver.Resolve(unit)
+	if err != nil {
+		return strings.ToUpper(unit)
+	}
+	return listing.Symbol
+}
+
+// usdPrice returns the USD price of one unit of a coin or fiat currency
+func (c *Converter) usdPrice(unit string) (float64, error) {
+	// fiat codes take precedence over coins sharing the symbol
+	if IsFiat(unit) {
+		return c.fiatPrice(strings.ToUpper(unit))
+	}
+
+	coin, err := c.coin(unit)
+	if err != nil {
+		return 0, err
+	}
+	return coin.PriceUsd, nil
+}
+
+// fiatPrice returns the USD price of one unit of a fiat currency, caching
+// its rate
+func (c *Converter) fiatPrice(currency string) (float64, error) {
+	c.mu.Lock()
+	rate, ok := c.rates[currency]
+	c.mu.Unlock()
+	if ok {
+		return 1 / rate, nil
+	}
+
+	rate, err := FetchFiatRate(currency)
+	if err != nil {
+		return 0, err
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("no rate for %s", currency)
+	}
+
+	c.mu.Lock()
+	c.rates[currency] = rate
+	c.mu.Unlock()
+	return 1 / rate, nil
+}
+
+// coin resolves a coin id, symbol or name, fetching and keeping the coin when
+// it isn't one of the converter's coins
+func (c *Converter) coin(unit string) (cmc.Coin, error) {
+	listing, err := c.resolver.Resolve(unit)
+	if err != nil {
+		return cmc.Coin{}, err
+	}
+
+	c.mu.Lock()
+	coin, ok := c.coins[listing.ID]
+	c.mu.Unlock()
+	if ok {
+		return coin, nil
+	}
+
+	coin, err = cmc.GetCoinData(listing.ID)
+	if err != nil {
+		return cmc.Coin{}, fmt.Errorf("%s: %v", listing.ID, err)
+	}
+
+	c.mu.Lock()
+	c.coins[listing.ID] = coin
+	c.mu.Unlock()
+	return coin, nil
+}
+
+// roundDigits are the significant digits converted amounts are rounded to
+const roundDigits = 8
+
+// Round rounds a converted amount to 8 significant digits, so amounts of
+// small coins aren't rounded away, while the whole units of larger amounts
+// are kept. ie. 0.00000012345678 | 123456789
+func Round(amount float64) float64 {
+	if amount == 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return amount
+	}
+	digits := roundDigits
+	if whole := int(math.Log10(math.Abs(amount))) + 1; whole > digits {
+		digits = whole
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(amount, 'g', digits, 64), 64)
+	if err != nil {
+		return amount
+	}
+	return rounded
+}
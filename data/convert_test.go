@@ -0,0 +1,81 @@
+package data
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "1.5 btc eth", want: []string{"1.5", "btc", "eth"}},
+		{input: "  1.5   btc  eth ", want: []string{"1.5", "btc", "eth"}},
+		{input: `1 "bitcoin cash" usd`, want: []string{"1", "bitcoin cash", "usd"}},
+		{input: `1 usd "bitcoin  cash"`, want: []string{"1", "usd", "bitcoin  cash"}},
+		{input: `1 "bitcoin cash`, want: []string{"1", "bitcoin cash"}},
+		{input: `1 "" usd`, want: []string{"1", "", "usd"}},
+		{input: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := splitInput(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitInput(%q) got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseConversion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Conversion
+		err   string
+	}{
+		{input: "1.5 btc eth", want: Conversion{Amount: 1.5, From: "btc", To: "eth"}},
+		{input: "1,000 usd btc", want: Conversion{Amount: 1000, From: "usd", To: "btc"}},
+		{input: `2 "bitcoin cash" eur`, want: Conversion{Amount: 2, From: "bitcoin cash", To: "eur"}},
+		{input: "1e-3 btc usd", want: Conversion{Amount: 0.001, From: "btc", To: "usd"}},
+		{input: "1 bitcoin cash usd", err: "expected <amount> <from> <to>"},
+		{input: "1 btc", err: "expected <amount> <from> <to>"},
+		{input: "", err: "expected <amount> <from> <to>"},
+		{input: "one btc usd", err: `invalid amount "one"`},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseConversion(splitInput(tt.input))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseConversion(%q) got error %v, want %q", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseConversion(%q) got error %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseConversion(%q) got %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount, want float64
+	}{
+		{amount: 24.312345678912, want: 24.312346},
+		{amount: 0.000000012345678912, want: 0.000000012345679},
+		{amount: 0.00000000001, want: 0.00000000001},
+		{amount: 123456789.123, want: 123456789},
+		{amount: -1.23456789123, want: -1.2345679},
+		{amount: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.amount); got != tt.want {
+			t.Errorf("Round(%v) got %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"math"
+	"sort"
+)
+
+// Resolution returns the typical interval between the points of the series
+// in seconds
+func Resolution(series [][]float64) int64 {
+	return int64(medianInterval(series) / 1000)
+}
+
+// Align samples the [timestamp in ms, value] series at each interval from
+// the start to the end unix timestamps, taking the last value at or before
+// each time. Times before the first point are NaN
+func Align(series [][]float64, start, end, interval int64) []float64 {
+	if interval <= 0 {
+		return nil
+	}
+	var values []float64
+	for t := start; t <= end; t += interval {
+		ms := float64(t) * 1000
+		i := sort.Search(len(series), func(i int) bool { return series[i][0] > ms })
+		if i == 0 {
+			values = append(values, math.NaN())
+			continue
+		}
+		values = append(values, series[i-1][1])
+	}
+	return values
+}
+
+// Returns returns the log returns between consecutive values, NaN where
+// either value is missing or not positive
+func Returns(values []float64) []float64 {
+	if len(values) < 2 {
+		return nil
+	}
+	returns := make([]float64, len(values)-1)
+	for i := range returns {
+		a, b := values[i], values[i+1]
+		if a > 0 && b > 0 {
+			returns[i] = math.Log(b / a)
+		} else {
+			returns[i] = math.NaN()
+		}
+	}
+	return returns
+}
+
+// Pearson returns the Pearson correlation of x and y over the indexes where
+// both are numbers, or NaN when there are fewer than three of them. A series
+// that doesn't vary is uncorrelated, 0
+func Pearson(x, y []float64) float64 {
+	var n, sumX, sumY float64
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		n++
+		sumX += x[i]
+		sumY += y[i]
+	}
+	if n < 3 {
+		return math.NaN()
+	}
+
+	meanX, meanY := sumX/n, sumY/n
+	var cov, varX, varY float64
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// CorrelationMatrix returns the Pearson correlation of each pair of series
+func CorrelationMatrix(series [][]float64) [][]float64 {
+	matrix := make([][]float64, len(series))
+	for i := range series {
+		matrix[i] = make([]float64, len(series))
+		for j := range series {
+			if j < i {
+				matrix[i][j] = matrix[j][i]
+				continue
+			}
+			matrix[i][j] = Pearson(series[i], series[j])
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,118 @@
+package data
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPearson(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		{name: "correlated", x: []float64{1, 2, 3, 4}, y: []float64{10, 20, 30, 40}, want: 1},
+		{name: "anti-correlated", x: []float64{1, 2, 3, 4}, y: []float64{-2, -4, -6, -8}, want: -1},
+		{name: "uncorrelated", x: []float64{1, -1, 1, -1}, y: []float64{1, 1, -1, -1}, want: 0},
+		{name: "constant", x: []float64{1, 2, 3, 4}, y: []float64{5, 5, 5, 5}, want: 0},
+		{name: "both constant", x: []float64{0, 0, 0}, y: []float64{0, 0, 0}, want: 0},
+		{name: "skips NaN", x: []float64{1, nan, 2, 3, 4}, y: []float64{2, 100, 4, 6, nan}, want: 1},
+		{name: "too short", x: []float64{1, 2}, y: []float64{1, 2}, want: nan},
+		{name: "too few numbers", x: []float64{1, 2, nan, 4}, y: []float64{1, nan, 3, 4}, want: nan},
+		{name: "empty", want: nan},
+	}
+
+	for _, tt := range tests {
+		got := Pearson(tt.x, tt.y)
+		if math.IsNaN(tt.want) {
+			if !math.IsNaN(got) {
+				t.Errorf("%s: got %v, want NaN", tt.name, got)
+			}
+			continue
+		}
+		if math.IsNaN(got) || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	// points at 100s, 160s and 400s
+	series := [][]float64{{100000, 1}, {160000, 2}, {400000, 3}}
+	got := Align(series, 0, 500, 100)
+	want := []float64{math.NaN(), 1, 2, 2, 3, 3}
+	if len(got) != len(want) || !math.IsNaN(got[0]) || !reflect.DeepEqual(got[1:], want[1:]) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := Align(series, 0, 500, 0); got != nil {
+		t.Errorf("got %v for a 0 interval, want nil", got)
+	}
+}
+
+func TestReturns(t *testing.T) {
+	got := Returns([]float64{1, math.E, math.NaN(), 2, 0, 1})
+	if len(got) != 5 || math.Abs(got[0]-1) > 1e-9 || !math.IsNaN(got[1]) || !math.IsNaN(got[2]) || !math.IsNaN(got[3]) || !math.IsNaN(got[4]) {
+		t.Errorf("got %v, want [1 NaN NaN NaN NaN]", got)
+	}
+
+	for _, values := range [][]float64{nil, {1}} {
+		if got := Returns(values); got != nil {
+			t.Errorf("Returns(%v) got %v, want nil", values, got)
+		}
+	}
+}
+
+func TestCorrelationMatrixMisaligned(t *testing.T) {
+	price := func(i int) float64 {
+		return 100 + 10*math.Sin(float64(i))
+	}
+
+	// a's points are on the minute, b's half a minute before with twice
+	// the price and every fifth missing, c moves against a
+	var a, b, c [][]float64
+	for i := 0; i <= 60; i++ {
+		ms := float64(i * 60000)
+		a = append(a, []float64{ms, price(i)})
+		if i%5 != 4 {
+			b = append(b, []float64{ms - 30000, 2 * price(i)})
+		}
+		c = append(c, []float64{ms + 1000, 1e4 / price(i)})
+	}
+
+	// c's points land just after each minute, so sample a second late
+	var series [][]float64
+	for _, s := range [][][]float64{a, b, c} {
+		series = append(series, Returns(Align(s, 61, 3541, 60)))
+	}
+	matrix := CorrelationMatrix(series)
+
+	for i := range matrix {
+		if math.Abs(matrix[i][i]-1) > 1e-9 {
+			t.Errorf("got %v on the diagonal, want 1", matrix[i][i])
+		}
+	}
+	if matrix[0][1] < 0.5 || matrix[0][1] != matrix[1][0] {
+		t.Errorf("got a and b %v, %v, want a symmetric strong correlation", matrix[0][1], matrix[1][0])
+	}
+	if math.Abs(matrix[0][2]+1) > 1e-9 {
+		t.Errorf("got a and c %v, want -1", matrix[0][2])
+	}
+}
+
+func TestCorrelationMatrixShortWindow(t *testing.T) {
+	// windows of one and no returns
+	for _, values := range [][]float64{{1, 2}, {1}} {
+		returns := Returns(values)
+		matrix := CorrelationMatrix([][]float64{returns, returns})
+		for _, row := range matrix {
+			for _, value := range row {
+				if !math.IsNaN(value) {
+					t.Errorf("got %v for returns %v, want NaN", matrix, returns)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package data
+
+import (
+	"fmt"
+)
+
+var globalGraphURL = "https://graphs2.coinmarketcap.com/global"
+
+// GlobalMarketGraph is the global market history
+type GlobalMarketGraph struct {
+	MarketCapUsd [][]float64 `json:"market_cap_by_available_supply"`
+	VolumeUsd    [][]float64 `json:"volume_usd"`
+}
+
+// DominanceGraph is the market cap dominance history
+type DominanceGraph struct {
+	Bitcoin  [][]float64 `json:"bitcoin"`
+	Ethereum [][]float64 `json:"ethereum"`
+	Others   [][]float64 `json:"others"`
+}
+
+// GetGlobalMarketGraph gets the total market cap and volume history
+func GetGlobalMarketGraph(start int64, end int64) (GlobalMarketGraph, error) {
+	var data GlobalMarketGraph
+	url := fmt.Sprintf("%s/marketcap-total/%d/%d/", globalGraphURL, start*1000, end*1000)
+	err := getJSON(url, &data)
+	return data, err
+}
+
+// GetDominanceGraph gets the market cap dominance history
+func GetDominanceGraph(start int64, end int64) (DominanceGraph, error) {
+	var data DominanceGraph
+	url := fmt.Sprintf("%s/dominance/%d/%d/", globalGraphURL, start*1000, end*1000)
+	err := getJSON(url, &data)
+	return data, err
+}
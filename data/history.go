@@ -0,0 +1,121 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/miguelmota/cryptocharts/daterange"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// CoinHistory fetches the coin's history over the date range. An all range
+// starts at the first point and a since-ath range at the highest price, and
+// the returned range has the start set
+func CoinHistory(coin string, r daterange.Range) (cmc.CoinGraph, daterange.Range, error) {
+	graph, err := cmc.GetCoinGraphData(coin, r.Start, r.End)
+	if err != nil {
+		return graph, r, err
+	}
+
+	if r.IsSinceATH() {
+		start := highTime(graph.PriceUsd)
+		graph = cmc.CoinGraph{
+			MarketCapByAvailableAupply: sinceTime(graph.MarketCapByAvailableAupply, start),
+			PriceBtc:                   sinceTime(graph.PriceBtc, start),
+			PriceUsd:                   sinceTime(graph.PriceUsd, start),
+			VolumeUsd:                  sinceTime(graph.VolumeUsd, start),
+		}
+	}
+
+	return graph, startFrom(r, graph.PriceUsd), nil
+}
+
+// GlobalHistory fetches the total market cap, volume and dominance history
+// over the date range. A since-ath range starts at the highest total market
+// cap
+func GlobalHistory(r daterange.Range) (GlobalMarketGraph, DominanceGraph, daterange.Range, error) {
+	marketGraph, err := GetGlobalMarketGraph(r.Start, r.End)
+	if err != nil {
+		return marketGraph, DominanceGraph{}, r, err
+	}
+
+	if r.IsSinceATH() {
+		start := highTime(marketGraph.MarketCapUsd)
+		marketGraph = GlobalMarketGraph{
+			MarketCapUsd: sinceTime(marketGraph.MarketCapUsd, start),
+			VolumeUsd:    sinceTime(marketGraph.VolumeUsd, start),
+		}
+	}
+	r = startFrom(r, marketGraph.MarketCapUsd)
+
+	dominanceGraph, err := GetDominanceGraph(r.Start, r.End)
+	if err != nil {
+		return marketGraph, dominanceGraph, r, err
+	}
+
+	return marketGraph, dominanceGraph, r, nil
+}
+
+// startFrom sets the start of all and since-ath ranges to the first point
+func startFrom(r daterange.Range, series [][]float64) daterange.Range {
+	if (r.Name == daterange.All || r.IsSinceATH()) && len(series) > 0 {
+		return r.SetStart(int64(series[0][0]) / 1000)
+	}
+	return r
+}
+
+// highTime returns the timestamp in ms of the highest value in the series
+func highTime(series [][]float64) float64 {
+	high := -1
+	for i, point := range series {
+		if high < 0 || point[1] > series[high][1] {
+			high = i
+		}
+	}
+	if high < 0 {
+		return 0
+	}
+	return series[high][0]
+}
+
+// sinceTime returns the points of the series from the timestamp in ms
+func sinceTime(series [][]float64, start float64) [][]float64 {
+	for i, point := range series {
+		if point[0] >= start {
+			return series[i:]
+		}
+	}
+	return nil
+}
+
+// GraphBetween returns the points of the coin's history between the unix
+// timestamps
+func GraphBetween(graph cmc.CoinGraph, start, end int64) cmc.CoinGraph {
+	return cmc.CoinGraph{
+		MarketCapByAvailableAupply: between(graph.MarketCapByAvailableAupply, start, end),
+		PriceBtc:                   between(graph.PriceBtc, start, end),
+		PriceUsd:                   between(graph.PriceUsd, start, end),
+		VolumeUsd:                  between(graph.VolumeUsd, start, end),
+	}
+}
+
+// between returns the points of the series between the unix timestamps
+func between(series [][]float64, start, end int64) [][]float64 {
+	from, to := float64(start)*1000, float64(end)*1000
+	i := sort.Search(len(series), func(i int) bool { return series[i][0] >= from })
+	j := sort.Search(len(series), func(i int) bool { return series[i][0] > to })
+	return series[i:j]
+}
+
+// AllTimeHigh fetches the coin's highest USD price over all its history,
+// and the unix timestamp its history starts at
+func AllTimeHigh(coin string) (float64, int64, error) {
+	r, err := daterange.ParseRange(daterange.All)
+	if err != nil {
+		return 0, 0, err
+	}
+	graph, r, err := CoinHistory(coin, r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return Stats(graph.PriceUsd).High, r.Start, nil
+}
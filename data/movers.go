@@ -0,0 +1,80 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// ChangeWindows are the % change windows of a coin
+var ChangeWindows = []string{"1h", "24h", "7d"}
+
+// ValidateChangeWindow returns an error for windows other than the change
+// windows
+func ValidateChangeWindow(window string) error {
+	for _, w := range ChangeWindows {
+		if w == window {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %% change window %q, expected one of %s", window, strings.Join(ChangeWindows, " | "))
+}
+
+// PercentChange returns the coin's % change for the change window, which
+// should be validated with ValidateChangeWindow. ie. 1h | 24h | 7d
+func PercentChange(coin cmc.Coin, window string) float64 {
+	switch window {
+	case "1h":
+		return coin.PercentChange1h
+	case "7d":
+		return coin.PercentChange7d
+	default:
+		return coin.PercentChange24h
+	}
+}
+
+// SortByMarketCap returns the coins sorted by market cap, largest first
+func SortByMarketCap(coinsMap map[string]cmc.Coin) []cmc.Coin {
+	var coins []cmc.Coin
+	for _, coin := range coinsMap {
+		coins = append(coins, coin)
+	}
+
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].MarketCapUsd > coins[j].MarketCapUsd
+	})
+
+	return coins
+}
+
+// TopMovers returns up to count coins with the largest gains and losses over
+// the change window, skipping coins below the minimum market cap or volume
+func TopMovers(coins []cmc.Coin, window string, count int, minCap float64, minVolume float64) ([]cmc.Coin, []cmc.Coin) {
+	var eligible []cmc.Coin
+	for _, coin := range coins {
+		if coin.MarketCapUsd < minCap || coin.Usd24hVolume < minVolume {
+			continue
+		}
+		eligible = append(eligible, coin)
+	}
+
+	sort.Slice(eligible, func(i, j int) bool {
+		return PercentChange(eligible[i], window) > PercentChange(eligible[j], window)
+	})
+
+	var gainers, losers []cmc.Coin
+	for i := 0; i < len(eligible) && i < count; i++ {
+		if PercentChange(eligible[i], window) > 0 {
+			gainers = append(gainers, eligible[i])
+		}
+	}
+	for i := len(eligible) - 1; i >= 0 && len(eligible)-1-i < count; i-- {
+		if PercentChange(eligible[i], window) < 0 {
+			losers = append(losers, eligible[i])
+		}
+	}
+
+	return gainers, losers
+}
@@ -0,0 +1,68 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+func TestTopMovers(t *testing.T) {
+	coins := []cmc.Coin{
+		{ID: "a", MarketCapUsd: 1e9, Usd24hVolume: 1e7, PercentChange1h: 1, PercentChange24h: 10, PercentChange7d: -30},
+		{ID: "b", MarketCapUsd: 1e9, Usd24hVolume: 1e7, PercentChange1h: -2, PercentChange24h: 20, PercentChange7d: 5},
+		{ID: "c", MarketCapUsd: 1e9, Usd24hVolume: 1e7, PercentChange1h: 3, PercentChange24h: -5, PercentChange7d: 15},
+		{ID: "d", MarketCapUsd: 1e9, Usd24hVolume: 1e7, PercentChange1h: -4, PercentChange24h: -15, PercentChange7d: 0},
+		{ID: "small", MarketCapUsd: 1e5, Usd24hVolume: 1e7, PercentChange1h: 90, PercentChange24h: 90, PercentChange7d: 90},
+		{ID: "illiquid", MarketCapUsd: 1e9, Usd24hVolume: 10, PercentChange1h: -90, PercentChange24h: -90, PercentChange7d: -90},
+	}
+
+	tests := []struct {
+		name      string
+		window    string
+		count     int
+		minCap    float64
+		minVolume float64
+		gainers   []string
+		losers    []string
+	}{
+		{name: "24h", window: "24h", count: 3, minCap: 1e6, minVolume: 1e3, gainers: []string{"b", "a"}, losers: []string{"d", "c"}},
+		{name: "1h", window: "1h", count: 3, minCap: 1e6, minVolume: 1e3, gainers: []string{"c", "a"}, losers: []string{"d", "b"}},
+		{name: "7d skips no change", window: "7d", count: 3, minCap: 1e6, minVolume: 1e3, gainers: []string{"c", "b"}, losers: []string{"a"}},
+		{name: "count", window: "24h", count: 1, minCap: 1e6, minVolume: 1e3, gainers: []string{"b"}, losers: []string{"d"}},
+		{name: "no minimums", window: "24h", count: 1, gainers: []string{"small"}, losers: []string{"illiquid"}},
+		{name: "min cap only", window: "24h", count: 1, minCap: 1e6, gainers: []string{"b"}, losers: []string{"illiquid"}},
+		{name: "min volume only", window: "24h", count: 1, minVolume: 1e3, gainers: []string{"small"}, losers: []string{"d"}},
+	}
+
+	ids := func(coins []cmc.Coin) []string {
+		var ids []string
+		for _, coin := range coins {
+			ids = append(ids, coin.ID)
+		}
+		return ids
+	}
+
+	for _, tt := range tests {
+		gainers, losers := TopMovers(coins, tt.window, tt.count, tt.minCap, tt.minVolume)
+		if !reflect.DeepEqual(ids(gainers), tt.gainers) {
+			t.Errorf("%s: got gainers %v, want %v", tt.name, ids(gainers), tt.gainers)
+		}
+		if !reflect.DeepEqual(ids(losers), tt.losers) {
+			t.Errorf("%s: got losers %v, want %v", tt.name, ids(losers), tt.losers)
+		}
+	}
+}
+
+func TestValidateChangeWindow(t *testing.T) {
+	for _, window := range []string{"1h", "24h", "7d"} {
+		if err := ValidateChangeWindow(window); err != nil {
+			t.Errorf("ValidateChangeWindow(%q) got error %v", window, err)
+		}
+	}
+	for _, window := range []string{"", "30d", "1d", "24H"} {
+		if err := ValidateChangeWindow(window); err == nil {
+			t.Errorf("ValidateChangeWindow(%q) got no error", window)
+		}
+	}
+}
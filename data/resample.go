@@ -0,0 +1,106 @@
+package data
+
+import (
+	"math"
+	"sort"
+)
+
+// gapFactor is how many times the series' typical interval between points a
+// missing interval has to span to be drawn as a gap
+const gapFactor = 3
+
+// Sample is a point of a resampled series. Gap samples fall in a missing
+// interval of the series and have no value
+type Sample struct {
+	Time  float64
+	Value float64
+	Gap   bool
+}
+
+// Resample resamples a [timestamp, value] series to n samples, two per
+// evenly spaced time bucket. Buckets with points keep their low and high in
+// time order so the extremes stay visible, and empty buckets are
+// interpolated unless they fall in a missing interval of the series. Series
+// of at most n points are returned as they are
+func Resample(series [][]float64, n int) []Sample {
+	if len(series) == 0 || n < 2 {
+		return nil
+	}
+
+	if len(series) <= n {
+		samples := make([]Sample, len(series))
+		for i, p := range series {
+			samples[i] = Sample{Time: p[0], Value: p[1]}
+		}
+		return samples
+	}
+
+	samples := make([]Sample, 0, n)
+	t0, t1 := series[0][0], series[len(series)-1][0]
+	if t1 <= t0 {
+		for len(samples) < n {
+			samples = append(samples, Sample{Time: t0, Value: series[0][1]})
+		}
+		return samples
+	}
+
+	buckets := n / 2
+	step := (t1 - t0) / float64(buckets)
+	maxInterval := gapFactor * medianInterval(series)
+
+	j := 0 // first point not before the bucket
+	for b := 0; b < buckets; b++ {
+		start := t0 + float64(b)*step
+		end := start + step
+		if b == buckets-1 {
+			end = math.Inf(1)
+		}
+
+		low, high := -1, -1
+		for ; j < len(series) && series[j][0] < end; j++ {
+			if low < 0 || series[j][1] < series[low][1] {
+				low = j
+			}
+			if high < 0 || series[j][1] > series[high][1] {
+				high = j
+			}
+		}
+
+		if low >= 0 {
+			if high < low {
+				low, high = high, low
+			}
+			samples = append(samples,
+				Sample{Time: series[low][0], Value: series[low][1]},
+				Sample{Time: series[high][0], Value: series[high][1]},
+			)
+			continue
+		}
+
+		// the bucket falls between the points j-1 and j
+		prev, next := series[j-1], series[j]
+		for _, t := range []float64{start + step/4, start + step*3/4} {
+			if next[0]-prev[0] > maxInterval {
+				samples = append(samples, Sample{Time: t, Gap: true})
+				continue
+			}
+			value := prev[1] + (next[1]-prev[1])*(t-prev[0])/(next[0]-prev[0])
+			samples = append(samples, Sample{Time: t, Value: value})
+		}
+	}
+
+	return samples
+}
+
+// medianInterval returns the median time between the points of the series
+func medianInterval(series [][]float64) float64 {
+	if len(series) < 2 {
+		return 0
+	}
+	intervals := make([]float64, len(series)-1)
+	for i := range intervals {
+		intervals[i] = series[i+1][0] - series[i][0]
+	}
+	sort.Float64s(intervals)
+	return intervals[len(intervals)/2]
+}
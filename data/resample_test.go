@@ -0,0 +1,136 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+// series returns a [timestamp, value] series of the values a minute apart
+func series(values ...float64) [][]float64 {
+	s := make([][]float64, len(values))
+	for i, v := range values {
+		s[i] = []float64{float64(i * 60), v}
+	}
+	return s
+}
+
+// flat returns a series of n points of 1 a minute apart
+func flat(n int) [][]float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 1
+	}
+	return series(values...)
+}
+
+func TestResample(t *testing.T) {
+	spiky := flat(1000)
+	spiky[500][1] = 100
+	spiky[701][1] = -50
+
+	// an hour of points, a missing day, then another hour
+	gappy := flat(61)
+	for i := 0; i <= 60; i++ {
+		gappy = append(gappy, []float64{float64(86400 + i*60), 2})
+	}
+
+	tests := []struct {
+		name   string
+		series [][]float64
+		n      int
+		check  func(t *testing.T, samples []Sample)
+	}{
+		{
+			name:   "spikes survive",
+			series: spiky,
+			n:      20,
+			check: func(t *testing.T, samples []Sample) {
+				if len(samples) != 20 {
+					t.Fatalf("got %d samples, want 20", len(samples))
+				}
+				high, low := false, false
+				for _, s := range samples {
+					high = high || s.Value == 100
+					low = low || s.Value == -50
+				}
+				if !high || !low {
+					t.Errorf("lost the spike or the dip: %v", samples)
+				}
+			},
+		},
+		{
+			name:   "gaps stay gaps",
+			series: gappy,
+			n:      40,
+			check: func(t *testing.T, samples []Sample) {
+				gaps := 0
+				for _, s := range samples {
+					inGap := s.Time > 3600 && s.Time < 86400
+					if inGap && !s.Gap {
+						t.Errorf("sample %+v in the missing day isn't a gap", s)
+					}
+					if !inGap && s.Gap {
+						t.Errorf("sample %+v outside the missing day is a gap", s)
+					}
+					if s.Gap {
+						gaps++
+					}
+				}
+				if gaps == 0 {
+					t.Error("got no gaps")
+				}
+			},
+		},
+		{
+			name:   "short series pass through",
+			series: series(3, 1, 4, 1, 5),
+			n:      20,
+			check: func(t *testing.T, samples []Sample) {
+				want := []Sample{{0, 3, false}, {60, 1, false}, {120, 4, false}, {180, 1, false}, {240, 5, false}}
+				if !reflect.DeepEqual(samples, want) {
+					t.Errorf("got %v, want %v", samples, want)
+				}
+			},
+		},
+		{
+			name:   "series of the width pass through",
+			series: series(1, 2, 3, 4),
+			n:      4,
+			check: func(t *testing.T, samples []Sample) {
+				if len(samples) != 4 || samples[3].Value != 4 {
+					t.Errorf("got %v, want the 4 points", samples)
+				}
+			},
+		},
+		{
+			name:   "width 0",
+			series: flat(10),
+			n:      0,
+			check:  expectNoSamples,
+		},
+		{
+			name:   "width 1",
+			series: flat(10),
+			n:      1,
+			check:  expectNoSamples,
+		},
+		{
+			name:   "empty series",
+			series: nil,
+			n:      20,
+			check:  expectNoSamples,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, Resample(tt.series, tt.n))
+		})
+	}
+}
+
+func expectNoSamples(t *testing.T, samples []Sample) {
+	if len(samples) != 0 {
+		t.Errorf("got %v, want no samples", samples)
+	}
+}
@@ -0,0 +1,225 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listingTTL is how long the cached coin listing is used before refetching
+const listingTTL = 24 * time.Hour
+
+// maxCandidates is the most candidates listed for an ambiguous coin
+const maxCandidates = 10
+
+// Listing is the id, name, symbol and rank of a listed coin. The id is the
+// CoinMarketCap slug. ie. bitcoin-cash
+type Listing struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	Rank   int    `json:"rank,string"`
+}
+
+// String formats the listing for choosers and errors. ie. Bitcoin Cash (BCH,
+// #12) bitcoin-cash
+func (l Listing) String() string {
+	return fmt.Sprintf("%s (%s, #%d) %s", l.Name, l.Symbol, l.Rank, l.ID)
+}
+
+// AmbiguousError is returned when a coin query matches more than one coin,
+// ordered by rank
+type AmbiguousError struct {
+	Query      string
+	Candidates []Listing
+}
+
+func (e *AmbiguousError) Error() string {
+	var lines []string
+	for _, c := range e.Candidates {
+		lines = append(lines, "  "+c.String())
+	}
+	return fmt.Sprintf("%q matches more than one coin, use one of the ids:\n%s", e.Query, strings.Join(lines, "\n"))
+}
+
+// Resolver resolves coin ids, symbols and names to listed coins, backed by
+// a listing cached on disk
+type Resolver struct {
+	mu        sync.Mutex
+	listings  []Listing
+	fetched   time.Time
+	cachePath string
+}
+
+// NewResolver returns a resolver caching the listing at the cache path, or
+// only in memory when the path is empty
+func NewResolver(cachePath string) *Resolver {
+	return &Resolver{cachePath: cachePath}
+}
+
+var defaultResolver = NewResolver(defaultListingPath())
+
+// defaultListingPath returns the listing cache path in the user cache dir
+func defaultListingPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cryptocharts", "listing.json")
+}
+
+// ResolveCoin resolves a coin id, symbol or name with the default resolver
+func ResolveCoin(query string) (Listing, error) {
+	return defaultResolver.Resolve(query)
+}
+
+// ResolveCoinExact resolves an exact coin id or symbol with the default
+// resolver
+func ResolveCoinExact(query string) (Listing, error) {
+	return defaultResolver.ResolveExact(query)
+}
+
+// Resolve returns the coin matching the query case-insensitively, trying the
+// id, then the symbol, then the name, then names and ids containing the
+// query. An *AmbiguousError lists the candidates when more than one coin
+// matches
+func (r *Resolver) Resolve(query string) (Listing, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	slug := strings.Join(strings.Fields(q), "-")
+	return r.resolve(query, "a CoinMarketCap id, symbol or name. ie. bitcoin | BTC | \"bitcoin cash\"", []func(l Listing) bool{
+		func(l Listing) bool { return l.ID == q || l.ID == slug },
+		func(l Listing) bool { return strings.ToLower(l.Symbol) == q },
+		func(l Listing) bool { return strings.ToLower(l.Name) == q },
+		func(l Listing) bool {
+			return strings.HasPrefix(strings.ToLower(l.Name), q) || strings.HasPrefix(l.ID, slug)
+		},
+		func(l Listing) bool {
+			return strings.Contains(strings.ToLower(l.Name), q) || strings.Contains(l.ID, slug)
+		},
+	})
+}
+
+// ResolveExact returns the coin whose id or else symbol is the query,
+// case-insensitively, for clients that shouldn't get a coin they didn't ask
+// for. An *AmbiguousError lists the candidates when coins share the symbol
+func (r *Resolver) ResolveExact(query string) (Listing, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	return r.resolve(query, "a CoinMarketCap id or symbol. ie. bitcoin-cash | BCH", []func(l Listing) bool{
+		func(l Listing) bool { return l.ID == q },
+		func(l Listing) bool { return strings.ToLower(l.Symbol) == q },
+	})
+}
+
+// resolve returns the coin matching the query with the first matcher that
+// matches any coin, with the usage in the error for unknown coins
+func (r *Resolver) resolve(query string, usage string, matchers []func(l Listing) bool) (Listing, error) {
+	if strings.TrimSpace(query) == "" {
+		return Listing{}, fmt.Errorf("no coin given")
+	}
+
+	listings, err := r.Listings()
+	if err != nil {
+		return Listing{}, err
+	}
+
+	for _, match := range matchers {
+		var candidates []Listing
+		for _, l := range listings {
+			if match(l) {
+				candidates = append(candidates, l)
+			}
+		}
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+		if len(candidates) > 1 {
+			if len(candidates) > maxCandidates {
+				candidates = candidates[:maxCandidates]
+			}
+			return Listing{}, &AmbiguousError{Query: query, Candidates: candidates}
+		}
+	}
+
+	return Listing{}, fmt.Errorf("unknown coin %q, use %s", query, usage)
+}
+
+// Listings returns every listed coin ordered by rank, from memory, the cache
+// file when it's fresh, or the API. A stale cache is used when the API fails
+func (r *Resolver) Listings() ([]Listing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.listings != nil && time.Since(r.fetched) < listingTTL {
+		return r.listings, nil
+	}
+
+	cached, fetched, cacheErr := r.readCache()
+	if cacheErr == nil && time.Since(fetched) < listingTTL {
+		r.listings, r.fetched = cached, fetched
+		return r.listings, nil
+	}
+
+	coins, err := FetchCoins(0, 0)
+	if err != nil {
+		if cacheErr == nil {
+			r.listings, r.fetched = cached, fetched
+			return r.listings, nil
+		}
+		return nil, err
+	}
+
+	listings := make([]Listing, len(coins))
+	for i, coin := range coins {
+		listings[i] = Listing{ID: coin.ID, Name: coin.Name, Symbol: coin.Symbol, Rank: coin.Rank}
+	}
+	sort.SliceStable(listings, func(i, j int) bool {
+		return listings[i].Rank < listings[j].Rank
+	})
+
+	r.listings, r.fetched = listings, time.Now()
+	r.writeCache()
+	return r.listings, nil
+}
+
+// readCache reads the cached listing and when it was fetched
+func (r *Resolver) readCache() ([]Listing, time.Time, error) {
+	if r.cachePath == "" {
+		return nil, time.Time{}, fmt.Errorf("no listing cache")
+	}
+	info, err := os.Stat(r.cachePath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	body, err := ioutil.ReadFile(r.cachePath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var listings []Listing
+	err = json.Unmarshal(body, &listings)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return listings, info.ModTime(), nil
+}
+
+// writeCache writes the listing to the cache file, failures only cost a
+// refetch next time so they're ignored
+func (r *Resolver) writeCache() {
+	if r.cachePath == "" {
+		return
+	}
+	body, err := json.Marshal(r.listings)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(r.cachePath), 0755) != nil {
+		return
+	}
+	ioutil.WriteFile(r.cachePath, body, 0644)
+}
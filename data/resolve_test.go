@@ -0,0 +1,153 @@
+package data
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testResolver returns a resolver over the listings, read from a fresh cache
+// file so nothing is fetched
+func testResolver(t *testing.T, listings []Listing) *Resolver {
+	path := filepath.Join(t.TempDir(), "listing.json")
+	body, err := json.Marshal(listings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path, body, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewResolver(path)
+}
+
+func TestResolverResolve(t *testing.T) {
+	r := testResolver(t, []Listing{
+		{ID: "bitcoin", Name: "Bitcoin", Symbol: "BTC", Rank: 1},
+		{ID: "ethereum", Name: "Ethereum", Symbol: "ETH", Rank: 2},
+		{ID: "bitcoin-cash", Name: "Bitcoin Cash", Symbol: "BCH", Rank: 12},
+		{ID: "ethereum-classic", Name: "Ethereum Classic", Symbol: "ETC", Rank: 20},
+		{ID: "litecoin", Name: "Litecoin", Symbol: "LTC", Rank: 21},
+		{ID: "bitcoin-gold", Name: "Bitcoin Gold", Symbol: "BTG", Rank: 90},
+		{ID: "bitgem", Name: "Bitgem", Symbol: "BTG", Rank: 900},
+		{ID: "eth", Name: "Ether Token", Symbol: "ETHT", Rank: 1000},
+		{ID: "ltc-token", Name: "Lite Token", Symbol: "LTC2", Rank: 1100},
+	})
+
+	tests := []struct {
+		query      string
+		want       string
+		candidates []string
+		err        string
+	}{
+		{query: "bitcoin", want: "bitcoin"},
+		{query: "BTC", want: "bitcoin"},
+		{query: " Btc ", want: "bitcoin"},
+		{query: "BITCOIN CASH", want: "bitcoin-cash"},
+		{query: "bitcoin cash", want: "bitcoin-cash"},
+		{query: "Bitcoin-Cash", want: "bitcoin-cash"},
+		{query: "bch", want: "bitcoin-cash"},
+		{query: "ethereum classic", want: "ethereum-classic"},
+		// an exact id wins over a symbol, and a symbol over a name
+		{query: "eth", want: "eth"},
+		{query: "ETHT", want: "eth"},
+		{query: "ltc", want: "litecoin"},
+		{query: "ether token", want: "eth"},
+		// a name prefix wins over a substring
+		{query: "lite", candidates: []string{"litecoin", "ltc-token"}},
+		{query: "litec", want: "litecoin"},
+		{query: "gem", want: "bitgem"},
+		{query: "btg", candidates: []string{"bitcoin-gold", "bitgem"}},
+		{query: "ether", candidates: []string{"ethereum", "ethereum-classic", "eth"}},
+		{query: "cash", want: "bitcoin-cash"},
+		{query: "dogecoin", err: "unknown coin"},
+		{query: " ", err: "no coin given"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Resolve(tt.query)
+		switch {
+		case tt.candidates != nil:
+			ambiguous, ok := err.(*AmbiguousError)
+			if !ok {
+				t.Errorf("Resolve(%q) got %v, %v, want an ambiguous error", tt.query, got, err)
+				continue
+			}
+			var ids []string
+			for _, c := range ambiguous.Candidates {
+				ids = append(ids, c.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.candidates) {
+				t.Errorf("Resolve(%q) got candidates %v, want %v", tt.query, ids, tt.candidates)
+			}
+		case tt.err != "":
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Resolve(%q) got error %v, want %q", tt.query, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("Resolve(%q) got error %v", tt.query, err)
+		case got.ID != tt.want:
+			t.Errorf("Resolve(%q) got %s, want %s", tt.query, got.ID, tt.want)
+		}
+	}
+}
+
+func TestResolverResolveExact(t *testing.T) {
+	r := testResolver(t, []Listing{
+		{ID: "bitcoin", Name: "Bitcoin", Symbol: "BTC", Rank: 1},
+		{ID: "bitcoin-cash", Name: "Bitcoin Cash", Symbol: "BCH", Rank: 12},
+		{ID: "bitcoin-gold", Name: "Bitcoin Gold", Symbol: "BTG", Rank: 90},
+		{ID: "bitgem", Name: "Bitgem", Symbol: "BTG", Rank: 900},
+		{ID: "btc", Name: "BTC Token", Symbol: "BTCT", Rank: 1000},
+	})
+
+	tests := []struct {
+		query     string
+		want      string
+		ambiguous bool
+	}{
+		{query: "bitcoin-cash", want: "bitcoin-cash"},
+		{query: "BCH", want: "bitcoin-cash"},
+		{query: "btc", want: "btc"},
+		{query: "btg", ambiguous: true},
+		{query: "bitcoin cash"},
+		{query: "bitc"},
+		{query: "cash"},
+	}
+
+	for _, tt := range tests {
+		got, err := r.ResolveExact(tt.query)
+		_, ambiguous := err.(*AmbiguousError)
+		switch {
+		case tt.ambiguous != ambiguous:
+			t.Errorf("ResolveExact(%q) got error %v, want ambiguous %v", tt.query, err, tt.ambiguous)
+		case tt.want == "" && !tt.ambiguous && err == nil:
+			t.Errorf("ResolveExact(%q) got %s, want no coin", tt.query, got.ID)
+		case tt.want != "" && got.ID != tt.want:
+			t.Errorf("ResolveExact(%q) got %s, %v, want %s", tt.query, got.ID, err, tt.want)
+		}
+	}
+}
+
+func TestAmbiguousErrorCandidates(t *testing.T) {
+	var listings []Listing
+	for i := 1; i <= maxCandidates+5; i++ {
+		listings = append(listings, Listing{ID: "coin-" + strings.Repeat("x", i), Name: "Coin", Symbol: "C", Rank: i})
+	}
+	r := testResolver(t, listings)
+
+	_, err := r.Resolve("c")
+	ambiguous, ok := err.(*AmbiguousError)
+	if !ok {
+		t.Fatalf("got error %v, want an ambiguous error", err)
+	}
+	if len(ambiguous.Candidates) != maxCandidates || ambiguous.Candidates[0].Rank != 1 {
+		t.Errorf("got %d candidates from rank %d, want the top %d", len(ambiguous.Candidates), ambiguous.Candidates[0].Rank, maxCandidates)
+	}
+	if !strings.Contains(err.Error(), "Coin (C, #1) coin-x") {
+		t.Errorf("got error %q, want the candidates listed", err)
+	}
+}
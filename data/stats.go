@@ -0,0 +1,85 @@
+package data
+
+import (
+	"math"
+	"time"
+)
+
+// RangeStats are the stats of a [timestamp, value] series over its range,
+// with timestamps in ms and changes in %
+type RangeStats struct {
+	High        float64
+	HighTime    float64
+	Low         float64
+	LowTime     float64
+	Change      float64 // from the first to the last point
+	MaxDrawdown float64 // largest fall from a high to a later low
+	Volatility  float64 // annualized standard deviation of log returns
+}
+
+// yearMs is a year in ms, for annualizing volatility
+const yearMs = float64(365 * 24 * time.Hour / time.Millisecond)
+
+// Stats returns the stats of the series
+func Stats(series [][]float64) RangeStats {
+	var stats RangeStats
+	if len(series) == 0 {
+		return stats
+	}
+
+	first, last := series[0][1], series[len(series)-1][1]
+	if first != 0 {
+		stats.Change = (last - first) / first * 100
+	}
+
+	stats.High, stats.HighTime = first, series[0][0]
+	stats.Low, stats.LowTime = first, series[0][0]
+	peak := first
+	for _, point := range series {
+		if point[1] > stats.High {
+			stats.High, stats.HighTime = point[1], point[0]
+		}
+		if point[1] < stats.Low {
+			stats.Low, stats.LowTime = point[1], point[0]
+		}
+		peak = math.Max(peak, point[1])
+		if peak > 0 {
+			stats.MaxDrawdown = math.Max(stats.MaxDrawdown, (peak-point[1])/peak*100)
+		}
+	}
+
+	// the sample standard deviation of log returns, scaled by the square
+	// root of the number of intervals in a year
+	var returns []float64
+	for i := 1; i < len(series); i++ {
+		if series[i-1][1] > 0 && series[i][1] > 0 {
+			returns = append(returns, math.Log(series[i][1]/series[i-1][1]))
+		}
+	}
+	interval := medianInterval(series)
+	if len(returns) < 2 || interval <= 0 {
+		return stats
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+	stats.Volatility = math.Sqrt(variance*yearMs/interval) * 100
+
+	return stats
+}
+
+// FromHigh returns the % change of the value from the high, which is the
+// value itself when it's higher
+func FromHigh(value, high float64) float64 {
+	if value >= high || high == 0 {
+		return 0
+	}
+	return (value - high) / high * 100
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+// spaced returns a series of the values interval ms apart
+func spaced(interval float64, values ...float64) [][]float64 {
+	s := make([][]float64, len(values))
+	for i, v := range values {
+		s[i] = []float64{float64(i) * interval, v}
+	}
+	return s
+}
+
+func TestStats(t *testing.T) {
+	day := float64(24 * 60 * 60 * 1000)
+	up, down := 100*math.Exp(0.01), 100.0
+
+	// the sample variance of n alternating returns of ±0.01 is
+	// n/(n-1) * 0.0001
+	daily := math.Sqrt(4.0/3*0.0001*365) * 100
+
+	tests := []struct {
+		name   string
+		series [][]float64
+		want   RangeStats
+		anyVol bool // the volatility isn't checked
+	}{
+		{name: "empty", series: nil, want: RangeStats{}},
+		{
+			name:   "single point",
+			series: spaced(day, 42),
+			want:   RangeStats{High: 42, Low: 42},
+		},
+		{
+			name:   "drawdown from the highest peak",
+			series: spaced(day, 100, 150, 100, 120, 60, 90),
+			want:   RangeStats{High: 150, HighTime: day, Low: 60, LowTime: 4 * day, Change: -10, MaxDrawdown: 60},
+			anyVol: true,
+		},
+		{
+			name:   "drawdown only after the peak",
+			series: spaced(day, 50, 100, 80),
+			want:   RangeStats{High: 100, HighTime: day, Low: 50, Change: 60, MaxDrawdown: 20},
+			anyVol: true,
+		},
+		{
+			name:   "daily volatility",
+			series: spaced(day, down, up, down, up, down),
+			want:   RangeStats{High: up, HighTime: day, Low: down, MaxDrawdown: (up - down) / up * 100, Volatility: daily},
+		},
+		{
+			name:   "hourly volatility",
+			series: spaced(day/24, down, up, down, up, down),
+			want:   RangeStats{High: up, HighTime: day / 24, Low: down, MaxDrawdown: (up - down) / up * 100, Volatility: daily * math.Sqrt(24)},
+		},
+	}
+
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) < 1e-9
+	}
+	for _, tt := range tests {
+		got := Stats(tt.series)
+		if !near(got.High, tt.want.High) || got.HighTime != tt.want.HighTime ||
+			!near(got.Low, tt.want.Low) || got.LowTime != tt.want.LowTime ||
+			!near(got.Change, tt.want.Change) || !near(got.MaxDrawdown, tt.want.MaxDrawdown) ||
+			(!tt.anyVol && !near(got.Volatility, tt.want.Volatility)) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFromHigh(t *testing.T) {
+	tests := []struct {
+		value, high, want float64
+	}{
+		{value: 50, high: 100, want: -50},
+		{value: 100, high: 100, want: 0},
+		{value: 120, high: 100, want: 0},
+		{value: 10, high: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := FromHigh(tt.value, tt.high); got != tt.want {
+			t.Errorf("FromHigh(%v, %v) got %v, want %v", tt.value, tt.high, got, tt.want)
+		}
+	}
+}
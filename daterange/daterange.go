@@ -0,0 +1,228 @@
+// Package daterange parses chart date ranges such as 7d, 1y6m, ytd or
+// absolute dates
+package daterange
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durations in seconds
+const (
+	OneMinute int64 = 60
+	OneHour         = OneMinute * 60
+	OneDay          = OneHour * 24
+	OneWeek         = OneDay * 7
+	OneMonth        = OneDay * 30
+	OneYear         = OneDay * 365
+)
+
+// named ranges
+const (
+	YTD      = "ytd"       // since the start of the year
+	All      = "all"       // all history
+	SinceATH = "since-ath" // since the all time high, found from the data
+)
+
+// unitSeconds are the seconds in each duration unit
+var unitSeconds = map[string]int64{
+	"n": OneMinute,
+	"h": OneHour,
+	"d": OneDay,
+	"w": OneWeek,
+	"m": OneMonth,
+	"y": OneYear,
+}
+
+var durationRegex = regexp.MustCompile(`^(\d+[nhdwmy])+$`)
+var durationPartRegex = regexp.MustCompile(`(\d+)([nhdwmy])`)
+
+// dateLayouts are the accepted -from and -to formats, in local time unless
+// they have a zone
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	time.RFC3339,
+}
+
+// Range is a date range
+type Range struct {
+	Start int64  // unix timestamp in seconds
+	End   int64  // unix timestamp in seconds
+	Name  string // relative or named range. ie. 7d | 1y6m | ytd, empty for absolute ranges
+}
+
+// ParseRange parses a date range such as 7d into a range ending now
+func ParseRange(dateRange string) (Range, error) {
+	return ParseRangeAt(dateRange, time.Now())
+}
+
+// ParseRangeAt parses a date range ending at now. A range is a duration of
+// one or more numbers and units, n for minutes, h, d, w, m for 30 days and y.
+// ie. 7d | 1y6m, or one of ytd | all | since-ath. since-ath starts at the all
+// time high, which is set from the data with SetStart
+func ParseRangeAt(dateRange string, now time.Time) (Range, error) {
+	name := strings.ToLower(strings.TrimSpace(dateRange))
+	if name == "" {
+		name = "7d"
+	}
+
+	secs := now.Unix()
+	r := Range{End: secs, Name: name}
+
+	switch name {
+	case YTD:
+		r.Start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location()).Unix()
+	case All, SinceATH:
+		return r, nil
+	default:
+		if !durationRegex.MatchString(name) {
+			return Range{}, fmt.Errorf("invalid date range %q, expected a duration such as 1h | 7d | 3m | 1y6m, or ytd | all | since-ath", dateRange)
+		}
+
+		span, err := durationSeconds(name)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid date range %q, %v", dateRange, err)
+		}
+		if span > secs {
+			return Range{}, fmt.Errorf("invalid date range %q, it starts before 1970", dateRange)
+		}
+		r.Start = secs - span
+	}
+
+	if r.Start >= r.End {
+		return Range{}, fmt.Errorf("invalid date range %q, it must start before it ends", dateRange)
+	}
+	return r, nil
+}
+
+// ParseDuration parses a duration of one or more numbers and units in
+// seconds, with the units of ParseRangeAt. ie. 1h | 90n | 1d12h
+func ParseDuration(duration string) (int64, error) {
+	value := strings.ToLower(strings.TrimSpace(duration))
+	if !durationRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid duration %q, expected numbers and units such as 1h | 90n | 1d12h", duration)
+	}
+
+	span, err := durationSeconds(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, %v", duration, err)
+	}
+	return span, nil
+}
+
+// durationSeconds sums the parts of a duration matching durationRegex in
+// seconds
+func durationSeconds(value string) (int64, error) {
+	var span int64
+	for _, part := range durationPartRegex.FindAllStringSubmatch(value, -1) {
+		n, err := strconv.ParseInt(part[1], 10, 64)
+		if err != nil && err.(*strconv.NumError).Err == strconv.ErrRange {
+			return 0, fmt.Errorf("the duration is too long")
+		}
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("durations must be greater than 0")
+		}
+
+		unit := unitSeconds[part[2]]
+		if n > math.MaxInt64/unit || span > math.MaxInt64-n*unit {
+			return 0, fmt.Errorf("the duration is too long")
+		}
+		span += n * unit
+	}
+	return span, nil
+}
+
+// Between parses an absolute range from the from date to the to date, which
+// defaults to now. Dates are YYYY-MM-DD, optionally with a HH:MM time, and
+// a to date without a time includes the whole day
+func Between(from, to string, now time.Time) (Range, error) {
+	start, _, err := parseDate(from, now)
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid -from date: %v", err)
+	}
+
+	end := now
+	if to != "" {
+		var dateOnly bool
+		end, dateOnly, err = parseDate(to, now)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid -to date: %v", err)
+		}
+		if dateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+		if end.After(now) {
+			end = now
+		}
+	}
+
+	if !start.Before(end) {
+		if to == "" {
+			to = "now"
+		}
+		return Range{}, fmt.Errorf("invalid date range, -from %s must be before -to %s", from, to)
+	}
+
+	return Range{Start: start.Unix(), End: end.Unix()}, nil
+}
+
+// parseDate parses a date in one of the date layouts, and whether it had no
+// time
+func parseDate(value string, now time.Time) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	for i, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, value, now.Location())
+		if err == nil {
+			return t, i == 0, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%q, expected YYYY-MM-DD or YYYY-MM-DD HH:MM", value)
+}
+
+// IsSinceATH returns true for ranges starting at the all time high
+func (r Range) IsSinceATH() bool {
+	return r.Name == SinceATH
+}
+
+// SetStart returns the range starting at the unix timestamp, for ranges whose
+// start is found from the data such as all and since-ath
+func (r Range) SetStart(start int64) Range {
+	r.Start = start
+	return r
+}
+
+// Label returns the range label with its precise bounds. ie. 7D (2024-03-24
+// to 2024-03-31) | 2024-01-01 to 2024-03-31
+func (r Range) Label() string {
+	// whole day ranges end at midnight, which is shown as the day before
+	end := r.End
+	if r.End-r.Start >= 2*OneDay && time.Unix(end, 0).Format("15:04:05") == "00:00:00" {
+		end--
+	}
+	bounds := fmt.Sprintf("%s to %s", r.formatTime(r.Start), r.formatTime(end))
+	if r.Name == "" {
+		return bounds
+	}
+
+	name := strings.ToUpper(strings.Replace(r.Name, "-", " ", -1))
+	if r.Start == 0 {
+		// the start of all and since-ath ranges isn't known until the data
+		// is fetched
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, bounds)
+}
+
+// formatTime formats a bound with the time for ranges under two days
+func (r Range) formatTime(unix int64) string {
+	if r.End-r.Start < 2*OneDay {
+		return time.Unix(unix, 0).Format("Jan 02 15:04")
+	}
+	return time.Unix(unix, 0).Format("2006-01-02")
+}
@@ -0,0 +1,177 @@
+package daterange
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// now is in local time, as Label formats in local time
+var now = time.Date(2024, time.January, 31, 12, 0, 0, 0, time.Local)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     int64
+		err      string
+	}{
+		{duration: "90n", want: 90 * OneMinute},
+		{duration: "1h", want: OneHour},
+		{duration: "1d12h", want: OneDay + 12*OneHour},
+		{duration: "2w", want: 2 * OneWeek},
+		{duration: "3m", want: 3 * OneMonth},
+		{duration: "1y6m", want: OneYear + 6*OneMonth},
+		{duration: " 1Y ", want: OneYear},
+		{duration: "", err: "expected numbers and units"},
+		{duration: "7", err: "expected numbers and units"},
+		{duration: "d", err: "expected numbers and units"},
+		{duration: "7x", err: "expected numbers and units"},
+		{duration: "-1d", err: "expected numbers and units"},
+		{duration: "0d", err: "greater than 0"},
+		{duration: "1d0h", err: "greater than 0"},
+		{duration: "99999999999999999999n", err: "too long"},
+		{duration: strconv.FormatInt(math.MaxInt64/OneYear+1, 10) + "y", err: "too long"},
+		{duration: strconv.FormatInt(math.MaxInt64/OneYear, 10) + "y1y", err: "too long"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.duration)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseDuration(%q) got error %v, want %q", tt.duration, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) got error %v", tt.duration, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) got %d, want %d", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeAt(t *testing.T) {
+	secs := now.Unix()
+	ytd := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local).Unix()
+
+	tests := []struct {
+		dateRange string
+		want      Range
+		err       string
+	}{
+		{dateRange: "", want: Range{Start: secs - 7*OneDay, End: secs, Name: "7d"}},
+		{dateRange: "1h", want: Range{Start: secs - OneHour, End: secs, Name: "1h"}},
+		{dateRange: "30n", want: Range{Start: secs - 30*OneMinute, End: secs, Name: "30n"}},
+		{dateRange: "1Y6M", want: Range{Start: secs - OneYear - 6*OneMonth, End: secs, Name: "1y6m"}},
+		{dateRange: "ytd", want: Range{Start: ytd, End: secs, Name: YTD}},
+		{dateRange: "all", want: Range{End: secs, Name: All}},
+		{dateRange: "since-ath", want: Range{End: secs, Name: SinceATH}},
+		{dateRange: "7", err: "expected a duration"},
+		{dateRange: "week", err: "expected a duration"},
+		{dateRange: "0d", err: "greater than 0"},
+		{dateRange: "100y", err: "before 1970"},
+		{dateRange: "99999999999999999999y", err: "too long"},
+		{dateRange: strconv.FormatInt(math.MaxInt64/OneMonth, 10) + "m1m", err: "too long"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRangeAt(tt.dateRange, now)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseRangeAt(%q) got error %v, want %q", tt.dateRange, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRangeAt(%q) got error %v", tt.dateRange, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRangeAt(%q) got %+v, want %+v", tt.dateRange, got, tt.want)
+		}
+	}
+}
+
+func TestParseRangeAtYearStart(t *testing.T) {
+	newYear := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+	_, err := ParseRangeAt("ytd", newYear)
+	if err == nil || !strings.Contains(err.Error(), "start before it ends") {
+		t.Errorf("got error %v, want the range to start before it ends", err)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	date := func(month time.Month, day, hour, min int) int64 {
+		return time.Date(2024, month, day, hour, min, 0, 0, time.Local).Unix()
+	}
+
+	tests := []struct {
+		from, to string
+		want     Range
+		err      string
+	}{
+		{from: "2024-01-01", to: "2024-01-10", want: Range{Start: date(1, 1, 0, 0), End: date(1, 11, 0, 0)}},
+		{from: "2024-01-01 09:30", to: "2024-01-01T17:00", want: Range{Start: date(1, 1, 9, 30), End: date(1, 1, 17, 0)}},
+		{from: "2024-01-01", want: Range{Start: date(1, 1, 0, 0), End: now.Unix()}},
+		{from: "2024-01-01", to: "2024-03-01", want: Range{Start: date(1, 1, 0, 0), End: now.Unix()}},
+		{from: "2024-01-31", to: "2024-01-31", want: Range{Start: date(1, 31, 0, 0), End: now.Unix()}},
+		{from: "2024-01-10", to: "2024-01-01", err: "must be before"},
+		{from: "2024-02-01", err: "must be before -to now"},
+		{from: "2024-01-01 12:00", to: "2024-01-01 12:00", err: "must be before"},
+		{from: "01/01/2024", err: "invalid -from date"},
+		{from: "2024-01-01", to: "tomorrow", err: "invalid -to date"},
+	}
+
+	for _, tt := range tests {
+		got, err := Between(tt.from, tt.to, now)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Between(%q, %q) got error %v, want %q", tt.from, tt.to, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Between(%q, %q) got error %v", tt.from, tt.to, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Between(%q, %q) got %+v, want %+v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	midnight := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.Local).Unix()
+	parse := func(dateRange string) Range {
+		r, err := ParseRangeAt(dateRange, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		r    Range
+		want string
+	}{
+		{r: parse("7d"), want: "7D (2024-01-24 to 2024-01-31)"},
+		{r: parse("1y6m"), want: "1Y6M (2022-08-04 to 2024-01-31)"},
+		{r: parse("6h"), want: "6H (Jan 31 06:00 to Jan 31 12:00)"},
+		{r: parse("ytd"), want: "YTD (2024-01-01 to 2024-01-31)"},
+		{r: parse("all"), want: "ALL"},
+		{r: parse("since-ath"), want: "SINCE ATH"},
+		{r: parse("since-ath").SetStart(midnight - 7*OneDay), want: "SINCE ATH (2024-01-24 to 2024-01-31)"},
+		{r: Range{Start: midnight - 7*OneDay, End: midnight}, want: "2024-01-24 to 2024-01-30"},
+		{r: Range{Start: midnight - OneDay, End: midnight}, want: "Jan 30 00:00 to Jan 31 00:00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.Label(); got != tt.want {
+			t.Errorf("%+v Label() got %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/miguelmota/cryptocharts/chartimage"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	"github.com/miguelmota/cryptocharts/theme"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// exportChart renders the coin's price history over the date range to an SVG
+// or PNG image, picked by the path extension, colored with the theme
+func exportChart(path, coin string, r daterange.Range, t theme.Theme, size, overlays string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".svg" && ext != ".png" {
+		return fmt.Errorf("unsupported export format %q, available formats: .svg | .png", ext)
+	}
+
+	width, height, err := parseSize(size)
+	if err != nil {
+		return err
+	}
+
+	chartOverlays, err := chartimage.ParseOverlays(overlays)
+	if err != nil {
+		return err
+	}
+
+	if coin == "" {
+		coin = "bitcoin"
+	}
+
+	coinInfo, err := cmc.GetCoinData(coin)
+	if err != nil {
+		return err
+	}
+
+	graphData, r, err := data.CoinHistory(coin, r)
+	if err != nil {
+		return err
+	}
+
+	chart := &chartimage.Chart{
+		Title:      fmt.Sprintf("%s %s: %s", coinInfo.Symbol, "Price History", r.Label()),
+		Series:     graphData.PriceUsd,
+		Overlays:   chartOverlays,
+		Width:      width,
+		Height:     height,
+		Color:      chartimage.ThemeColor(t.Accent, chartimage.Color("")),
+		Background: chartimage.ThemeColor(t.Background, color.RGBA{}),
+		Text:       chartimage.ThemeColor(t.Text, color.RGBA{}),
+		Border:     chartimage.ThemeColor(t.Border, color.RGBA{}),
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if ext == ".svg" {
+		err = chart.WriteSVG(f)
+	} else {
+		err = chart.WritePNG(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseSize parses an image size such as 1200x600
+func parseSize(size string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(size), "x")
+	if len(parts) == 2 {
+		width, err1 := strconv.Atoi(parts[0])
+		height, err2 := strconv.Atoi(parts[1])
+		if err1 == nil && err2 == nil && width >= 200 && height >= 100 {
+			return width, height, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT of at least 200x100. ie. 1200x600", size)
+}
@@ -0,0 +1,49 @@
+// Package format formats coin values for display
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// USD formats a US dollar amount. ie. $64,210.5
+func USD(value float64) string {
+	return fmt.Sprintf("$%s", humanize.Commaf(value))
+}
+
+// Percent formats a percentage. ie. 1.20%
+func Percent(value float64) string {
+	return fmt.Sprintf("%.2f%%", value)
+}
+
+// SignedPercent formats a percentage with its sign. ie. +1.20%
+func SignedPercent(value float64) string {
+	return fmt.Sprintf("%+.2f%%", value)
+}
+
+// Amount formats an amount of a coin. ie. 21,000,000 BTC
+func Amount(value float64, symbol string) string {
+	return fmt.Sprintf("%s %s", humanize.Commaf(value), symbol)
+}
+
+// Count formats an integer count. ie. 1,500
+func Count(value int) string {
+	return humanize.Comma(int64(value))
+}
+
+// Time formats a unix timestamp. ie. 15:04:05 Jan 02
+func Time(unix int64) string {
+	return time.Unix(unix, 0).Format("15:04:05 Jan 02")
+}
+
+// LastUpdated formats the last updated unix timestamp string of a coin
+func LastUpdated(lastUpdated string) (string, error) {
+	unix, err := strconv.ParseInt(lastUpdated, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	return Time(unix), nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miguelmota/cryptocharts/data"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// Exporter exports coin and global market gauges in the Prometheus text
+// exposition format
+type Exporter struct {
+	coinIDs     []string
+	limit       uint
+	refresh     time.Duration
+	mu          sync.RWMutex
+	coins       []cmc.Coin
+	global      cmc.GlobalMarketData
+	lastSuccess time.Time
+	duration    time.Duration
+	errors      int
+}
+
+// NewExporter returns a new exporter for the coin ids, or the top limit coins
+// when no ids are given, refetched every refresh interval
+func NewExporter(coinIDs []string, limit uint, refresh time.Duration) *Exporter {
+	return &Exporter{
+		coinIDs: coinIDs,
+		limit:   limit,
+		refresh: refresh,
+	}
+}
+
+// ListenAndServe starts the refresh loop and serves the metrics on addr
+func (e *Exporter) ListenAndServe(addr string) error {
+	e.fetch()
+
+	go func() {
+		ticker := time.NewTicker(e.refresh)
+		for range ticker.C {
+			e.fetch()
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+
+	log.Printf("serving metrics on %s/metrics", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// fetch refetches the coins and global data, recording latency and errors
+func (e *Exporter) fetch() {
+	start := time.Now()
+	coins, global, err := e.fetchData()
+	duration := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.duration = duration
+	if err != nil {
+		e.errors++
+		log.Println(err)
+		return
+	}
+
+	e.coins = coins
+	e.global = global
+	e.lastSuccess = time.Now()
+}
+
+func (e *Exporter) fetchData() ([]cmc.Coin, cmc.GlobalMarketData, error) {
+	var coins []cmc.Coin
+	if len(e.coinIDs) == 0 {
+		var err error
+		coins, err = data.FetchCoins(0, int(e.limit))
+		if err != nil {
+			return nil, cmc.GlobalMarketData{}, err
+		}
+	} else {
+		for _, id := range e.coinIDs {
+			coin, err := cmc.GetCoinData(id)
+			if err != nil {
+				return nil, cmc.GlobalMarketData{}, fmt.Errorf("%s: %v", id, err)
+			}
+			coins = append(coins, coin)
+		}
+	}
+
+	global, err := cmc.GetMarketData()
+	if err != nil {
+		return nil, cmc.GlobalMarketData{}, err
+	}
+
+	return coins, global, nil
+}
+
+// handleMetrics handles GET /metrics
+func (e *Exporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var buf bytes.Buffer
+
+	coinGauges := []struct {
+		name  string
+		help  string
+		value func(coin cmc.Coin) float64
+	}{
+		{"cryptocharts_coin_price_usd", "Coin price in USD.", func(c cmc.Coin) float64 { return c.PriceUsd }},
+		{"cryptocharts_coin_price_btc", "Coin price in BTC.", func(c cmc.Coin) float64 { return c.PriceBtc }},
+		{"cryptocharts_coin_market_cap_usd", "Coin market cap in USD.", func(c cmc.Coin) float64 { return c.MarketCapUsd }},
+		{"cryptocharts_coin_volume_24h_usd", "Coin 24 hour volume in USD.", func(c cmc.Coin) float64 { return c.Usd24hVolume }},
+		{"cryptocharts_coin_available_supply", "Coin available supply.", func(c cmc.Coin) float64 { return c.AvailableSupply }},
+		{"cryptocharts_coin_rank", "Coin rank by market cap.", func(c cmc.Coin) float64 { return float64(c.Rank) }},
+	}
+	for _, g := range coinGauges {
+		writeMetricHeader(&buf, g.name, g.help, "gauge")
+		for _, coin := range e.coins {
+			writeMetric(&buf, g.name, coinLabels(coin), g.value(coin))
+		}
+	}
+
+	name := "cryptocharts_coin_percent_change"
+	writeMetricHeader(&buf, name, "Coin % change over the window.", "gauge")
+	for _, coin := range e.coins {
+		for _, window := range data.ChangeWindows {
+			labels := fmt.Sprintf(`%s,window="%s"`, coinLabels(coin), window)
+			writeMetric(&buf, name, labels, data.PercentChange(coin, window))
+		}
+	}
+
+	globalGauges := []struct {
+		name  string
+		help  string
+		value float64
+	}{
+		{"cryptocharts_global_market_cap_usd", "Total market cap in USD.", e.global.TotalMarketCapUsd},
+		{"cryptocharts_global_volume_24h_usd", "Total 24 hour volume in USD.", e.global.Total24hVolumeUsd},
+		{"cryptocharts_global_bitcoin_dominance_percent", "Bitcoin % of total market cap.", e.global.BitcoinPercentageOfMarketCap},
+		{"cryptocharts_global_active_currencies", "Number of active currencies.", float64(e.global.ActiveCurrencies)},
+		{"cryptocharts_global_active_assets", "Number of active assets.", float64(e.global.ActiveAssets)},
+		{"cryptocharts_global_active_markets", "Number of active markets.", float64(e.global.ActiveMarkets)},
+	}
+	if !e.lastSuccess.IsZero() {
+		for _, g := range globalGauges {
+			writeMetricHeader(&buf, g.name, g.help, "gauge")
+			writeMetric(&buf, g.name, "", g.value)
+		}
+	}
+
+	writeMetricHeader(&buf, "cryptocharts_upstream_fetch_duration_seconds", "Duration of the last upstream fetch.", "gauge")
+	writeMetric(&buf, "cryptocharts_upstream_fetch_duration_seconds", "", e.duration.Seconds())
+
+	writeMetricHeader(&buf, "cryptocharts_upstream_fetch_errors_total", "Number of failed upstream fetches.", "counter")
+	writeMetric(&buf, "cryptocharts_upstream_fetch_errors_total", "", float64(e.errors))
+
+	if !e.lastSuccess.IsZero() {
+		writeMetricHeader(&buf, "cryptocharts_upstream_last_success_timestamp_seconds", "Unix time of the last successful upstream fetch.", "gauge")
+		writeMetric(&buf, "cryptocharts_upstream_last_success_timestamp_seconds", "", float64(e.lastSuccess.Unix()))
+
+		writeMetricHeader(&buf, "cryptocharts_upstream_data_age_seconds", "Age of the exported data.", "gauge")
+		writeMetric(&buf, "cryptocharts_upstream_data_age_seconds", "", time.Since(e.lastSuccess).Seconds())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(buf.Bytes())
+}
+
+func coinLabels(coin cmc.Coin) string {
+	return fmt.Sprintf(`id="%s",symbol="%s"`, escapeLabel(coin.ID), escapeLabel(coin.Symbol))
+}
+
+// escapeLabel escapes a label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func writeMetricHeader(buf *bytes.Buffer, name, help, typ string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeMetric(buf *bytes.Buffer, name, labels string, value float64) {
+	if labels != "" {
+		fmt.Fprintf(buf, "%s{%s} %g\n", name, labels, value)
+		return
+	}
+	fmt.Fprintf(buf, "%s %g\n", name, value)
+}
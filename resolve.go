@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/miguelmota/cryptocharts/data"
+)
+
+// resolveCoin resolves a coin id, symbol or name to its id. When more than
+// one coin matches and stdin is a terminal the candidates are offered in a
+// chooser, otherwise the error lists them
+func resolveCoin(query string) (string, error) {
+	listing, err := data.ResolveCoin(query)
+	if err == nil {
+		return listing.ID, nil
+	}
+
+	ambiguous, ok := err.(*data.AmbiguousError)
+	if !ok || !isTerminal(os.Stdin) {
+		return "", err
+	}
+
+	listing, err = chooseCoin(ambiguous)
+	if err != nil {
+		return "", err
+	}
+	return listing.ID, nil
+}
+
+// resolveCoins resolves a list of coin ids, symbols or names to their ids
+func resolveCoins(queries []string) ([]string, error) {
+	var ids []string
+	for _, query := range queries {
+		id, err := resolveCoin(query)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// chooseCoin prompts on stderr for one of the ambiguous candidates
+func chooseCoin(ambiguous *data.AmbiguousError) (data.Listing, error) {
+	fmt.Fprintf(os.Stderr, "%q matches more than one coin:\n", ambiguous.Query)
+	for i, c := range ambiguous.Candidates {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, c)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "choose 1-%d: ", len(ambiguous.Candidates))
+		line, err := reader.ReadString('\n')
+		n, convErr := strconv.Atoi(strings.TrimSpace(line))
+		if convErr == nil && n >= 1 && n <= len(ambiguous.Candidates) {
+			return ambiguous.Candidates[n-1], nil
+		}
+		if err != nil {
+			return data.Listing{}, ambiguous
+		}
+	}
+}
+
+// isTerminal reports whether the file is a terminal rather than a pipe or
+// file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
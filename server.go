@@ -0,0 +1,369 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	table "github.com/miguelmota/cryptocharts/table"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// Server serves the dashboard data over HTTP from one cached feed
+type Server struct {
+	limit       uint
+	refresh     time.Duration
+	mu          sync.RWMutex
+	coins       []*cmc.Coin
+	global      cmc.GlobalMarketData
+	updated     time.Time
+	history     map[string]*historyEntry
+	subscribers map[chan []byte]bool
+}
+
+type historyEntry struct {
+	value   interface{}
+	fetched time.Time
+}
+
+// globalHistory is the global market history over a date range
+type globalHistory struct {
+	MarketCapUsd [][]float64 `json:"market_cap_usd"`
+	VolumeUsd    [][]float64 `json:"volume_usd"`
+	Dominance    [][]float64 `json:"bitcoin_dominance"`
+}
+
+// snapshot is the data pushed to event stream subscribers on each refresh
+type snapshot struct {
+	Coins   []*cmc.Coin          `json:"coins"`
+	Global  cmc.GlobalMarketData `json:"global"`
+	Updated int64                `json:"updated"`
+}
+
+// NewServer returns a new server that caches the top limit coins and
+// refetches them every refresh interval
+func NewServer(limit uint, refresh time.Duration) *Server {
+	return &Server{
+		limit:       limit,
+		refresh:     refresh,
+		history:     map[string]*historyEntry{},
+		subscribers: map[chan []byte]bool{},
+	}
+}
+
+// Start fetches the data and starts the refresh loop
+func (s *Server) Start() error {
+	err := s.fetch()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.refresh)
+		for range ticker.C {
+			err := s.fetch()
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Handler returns the HTTP handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/coins", s.handleCoins)
+	mux.HandleFunc("/coins/", s.handleCoin)
+	mux.HandleFunc("/global", s.handleGlobal)
+	mux.HandleFunc("/global/history", s.handleGlobalHistory)
+	mux.HandleFunc("/events", s.handleEvents)
+	return mux
+}
+
+// fetch refetches the cached coins and global data and notifies subscribers
+func (s *Server) fetch() error {
+	coins, err := data.FetchCoins(0, int(s.limit))
+	if err != nil {
+		return err
+	}
+
+	global, err := cmc.GetMarketData()
+	if err != nil {
+		return err
+	}
+
+	cached := make([]*cmc.Coin, len(coins))
+	for i := range coins {
+		cached[i] = &coins[i]
+	}
+
+	s.mu.Lock()
+	s.coins = cached
+	s.global = global
+	s.updated = time.Now()
+	data, err := json.Marshal(snapshot{Coins: s.coins, Global: s.global, Updated: s.updated.Unix()})
+	for ch := range s.subscribers {
+		select {
+		case ch <- data:
+		default:
+			// drop the update for subscribers that aren't keeping up
+		}
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+// handleCoins handles GET /coins?sort=&desc=&limit=
+func (s *Server) handleCoins(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.RLock()
+	coins := make([]*cmc.Coin, len(s.coins))
+	copy(coins, s.coins)
+	totalMarketCap := s.global.TotalMarketCapUsd
+	s.mu.RUnlock()
+
+	sortBy := query.Get("sort")
+	if sortBy == "" {
+		sortBy = "rank"
+	}
+	desc := query.Get("desc") == "true"
+
+	err := table.SortCoins(coins, sortBy, desc, totalMarketCap)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if l := query.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", l))
+			return
+		}
+		if limit < len(coins) {
+			coins = coins[:limit]
+		}
+	}
+
+	writeJSON(w, coins)
+}
+
+// handleCoin handles GET /coins/{id} and GET /coins/{id}/history?range=
+func (s *Server) handleCoin(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/coins/"), "/"), "/")
+
+	// coins are given by exact id or symbol, so clients never silently get
+	// another coin
+	listing, err := data.ResolveCoinExact(parts[0])
+	if _, ok := err.(*data.AmbiguousError); ok {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	id := listing.ID
+
+	switch {
+	case len(parts) == 1 && id != "":
+		coin, err := s.coin(id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, coin)
+	case len(parts) == 2 && parts[1] == "history":
+		dateRange := r.URL.Query().Get("range")
+		if dateRange == "" {
+			dateRange = "7d"
+		}
+		rng, err := daterange.ParseRange(dateRange)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		graph, err := s.coinHistory(id, rng)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeJSON(w, graph)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleGlobal handles GET /global
+func (s *Server) handleGlobal(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	global := s.global
+	s.mu.RUnlock()
+
+	writeJSON(w, global)
+}
+
+// handleGlobalHistory handles GET /global/history?range=
+func (s *Server) handleGlobalHistory(w http.ResponseWriter, r *http.Request) {
+	dateRange := r.URL.Query().Get("range")
+	if dateRange == "" {
+		dateRange = "7d"
+	}
+
+	rng, err := daterange.ParseRange(dateRange)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	history, err := s.cachedHistory("global/"+historyKey(rng), func() (interface{}, error) {
+		marketGraph, dominanceGraph, _, err := data.GlobalHistory(rng)
+		if err != nil {
+			return nil, err
+		}
+		return globalHistory{
+			MarketCapUsd: marketGraph.MarketCapUsd,
+			VolumeUsd:    marketGraph.VolumeUsd,
+			Dominance:    dominanceGraph.Bitcoin,
+		}, nil
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, history)
+}
+
+// handleEvents handles GET /events, a server-sent event stream that pushes
+// the coins and global data on each refresh
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+
+	ch := make(chan []byte, 1)
+	s.mu.Lock()
+	s.subscribers[ch] = true
+	data, err := json.Marshal(snapshot{Coins: s.coins, Global: s.global, Updated: s.updated.Unix()})
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for {
+		fmt.Fprintf(w, "event: refresh\ndata: %s\n\n", data)
+		flusher.Flush()
+
+		select {
+		case data = <-ch:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// coin returns the cached coin, fetching coins outside the cached set
+func (s *Server) coin(id string) (*cmc.Coin, error) {
+	s.mu.RLock()
+	for _, coin := range s.coins {
+		if coin.ID == id {
+			s.mu.RUnlock()
+			return coin, nil
+		}
+	}
+	s.mu.RUnlock()
+
+	coin, err := cmc.GetCoinData(id)
+	if err != nil {
+		return nil, err
+	}
+	return &coin, nil
+}
+
+// coinHistory returns the coin's price history over the date range, cached
+// for the refresh interval
+func (s *Server) coinHistory(id string, r daterange.Range) (cmc.CoinGraph, error) {
+	graph, err := s.cachedHistory(fmt.Sprintf("%s/%s", id, historyKey(r)), func() (interface{}, error) {
+		graph, _, err := data.CoinHistory(id, r)
+		return graph, err
+	})
+	if err != nil {
+		return cmc.CoinGraph{}, err
+	}
+	return graph.(cmc.CoinGraph), nil
+}
+
+// historyKey returns the history cache key of a parsed date range, the same
+// for durations of the same length. ie. 7d | 1w
+func historyKey(r daterange.Range) string {
+	switch r.Name {
+	case daterange.YTD, daterange.All, daterange.SinceATH:
+		return r.Name
+	}
+	return fmt.Sprintf("%ds", r.End-r.Start)
+}
+
+// cachedHistory returns the history cached under key, fetching it when it's
+// older than the refresh interval
+func (s *Server) cachedHistory(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	s.mu.RLock()
+	entry, ok := s.history[key]
+	s.mu.RUnlock()
+	if ok && time.Since(entry.fetched) < s.refresh {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for k, e := range s.history {
+		if time.Since(e.fetched) >= s.refresh {
+			delete(s.history, k)
+		}
+	}
+	s.history[key] = &historyEntry{value: value, fetched: time.Now()}
+	s.mu.Unlock()
+
+	return value, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
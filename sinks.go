@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// Point is a single time series data point
+type Point struct {
+	Measurement string             `json:"measurement"`
+	Tags        map[string]string  `json:"tags,omitempty"`
+	Fields      map[string]float64 `json:"fields"`
+	Time        time.Time          `json:"time"`
+}
+
+// Sink writes points to a time series store
+type Sink interface {
+	Write(points []Point) error
+}
+
+// SinkConfig configures a sink
+type SinkConfig struct {
+	Type      string `json:"type"`      // influxdb | graphite | jsonl
+	URL       string `json:"url"`       // influxdb write url. ie. http://localhost:8086/write?db=crypto
+	Address   string `json:"address"`   // graphite host:port
+	Prefix    string `json:"prefix"`    // graphite metric prefix
+	Path      string `json:"path"`      // jsonl file path
+	BatchSize int    `json:"batchSize"` // points per write, 0 for the default
+	Retries   *int   `json:"retries"`   // retries per failed write, nil for the default
+}
+
+const (
+	defaultBatchSize = 500
+	defaultRetries   = 3
+	// defaultMaxPending caps the points kept for a sink while it's
+	// unavailable
+	defaultMaxPending = 100000
+)
+
+// newSink returns the sink for the config
+func newSink(config SinkConfig) (Sink, error) {
+	switch config.Type {
+	case "influxdb":
+		if config.URL == "" {
+			return nil, fmt.Errorf("influxdb sink requires a url")
+		}
+		return &InfluxSink{URL: config.URL, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "graphite":
+		if config.Address == "" {
+			return nil, fmt.Errorf("graphite sink requires an address")
+		}
+		return &GraphiteSink{Address: config.Address, Prefix: config.Prefix}, nil
+	case "jsonl":
+		if config.Path == "" {
+			return nil, fmt.Errorf("jsonl sink requires a path")
+		}
+		return &JSONLSink{Path: config.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q, available types: influxdb | graphite | jsonl", config.Type)
+	}
+}
+
+// InfluxSink writes points in the InfluxDB line protocol over HTTP
+type InfluxSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// Write implements Sink interface
+func (s *InfluxSink) Write(points []Point) error {
+	var buf bytes.Buffer
+	for _, p := range points {
+		buf.WriteString(escapeInflux(p.Measurement, false))
+		for _, k := range sortedKeys(p.Tags) {
+			fmt.Fprintf(&buf, ",%s=%s", escapeInflux(k, true), escapeInflux(p.Tags[k], true))
+		}
+		for i, k := range sortedFieldKeys(p.Fields) {
+			sep := ","
+			if i == 0 {
+				sep = " "
+			}
+			fmt.Fprintf(&buf, "%s%s=%v", sep, escapeInflux(k, true), p.Fields[k])
+		}
+		fmt.Fprintf(&buf, " %d\n", p.Time.UnixNano())
+	}
+
+	resp, err := s.Client.Post(s.URL, "text/plain; charset=utf-8", &buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("influxdb: %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// escapeInflux escapes measurement names, and tag keys and values when tag
+// is true
+func escapeInflux(s string, tag bool) string {
+	if tag {
+		return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(s)
+	}
+	return strings.NewReplacer(",", `\,`, " ", `\ `).Replace(s)
+}
+
+// GraphiteSink writes points in the Graphite plaintext protocol over TCP
+type GraphiteSink struct {
+	Address string
+	Prefix  string
+}
+
+// Write implements Sink interface
+func (s *GraphiteSink) Write(points []Point) error {
+	var buf bytes.Buffer
+	for _, p := range points {
+		path := []string{}
+		if s.Prefix != "" {
+			path = append(path, s.Prefix)
+		}
+		path = append(path, graphiteNode(p.Measurement))
+		if id, ok := p.Tags["id"]; ok {
+			path = append(path, graphiteNode(id))
+		}
+		for _, k := range sortedFieldKeys(p.Fields) {
+			fmt.Fprintf(&buf, "%s.%s %v %d\n", strings.Join(path, "."), graphiteNode(k), p.Fields[k], p.Time.Unix())
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", s.Address, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
+
+// graphiteNode replaces characters that aren't allowed in a metric path node
+func graphiteNode(s string) string {
+	return strings.NewReplacer(".", "_", " ", "_", "/", "_").Replace(s)
+}
+
+// JSONLSink appends points as JSON lines to a file
+type JSONLSink struct {
+	Path string
+}
+
+// Write implements Sink interface
+func (s *JSONLSink) Write(points []Point) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, p := range points {
+		err := enc.Encode(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(buf.Bytes())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// batchSink batches points for a sink and retries failed writes, points
+// that still fail are kept and sent with the next flush, dropping the
+// oldest past maxPending
+type batchSink struct {
+	name       string
+	sink       Sink
+	batchSize  int
+	retries    int
+	backoff    time.Duration
+	maxPending int
+	pending    []Point
+}
+
+func newBatchSink(config SinkConfig) (*batchSink, error) {
+	sink, err := newSink(config)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &batchSink{
+		name:       config.Type,
+		sink:       sink,
+		batchSize:  config.BatchSize,
+		retries:    defaultRetries,
+		backoff:    time.Second,
+		maxPending: defaultMaxPending,
+	}
+	if b.batchSize <= 0 {
+		b.batchSize = defaultBatchSize
+	}
+	if config.Retries != nil {
+		if *config.Retries < 0 {
+			return nil, fmt.Errorf("%s sink retries must be 0 or more", config.Type)
+		}
+		b.retries = *config.Retries
+	}
+	return b, nil
+}
+
+// Flush writes the pending and new points in batches
+func (b *batchSink) Flush(points []Point) error {
+	b.pending = append(b.pending, points...)
+
+	for len(b.pending) > 0 {
+		n := b.batchSize
+		if n > len(b.pending) {
+			n = len(b.pending)
+		}
+
+		err := b.writeWithRetry(b.pending[:n])
+		if err != nil {
+			if len(b.pending) > b.maxPending {
+				// copy so the dropped points' backing array is released
+				b.pending = append([]Point(nil), b.pending[len(b.pending)-b.maxPending:]...)
+			}
+			return fmt.Errorf("%s sink: %v (%d points pending)", b.name, err, len(b.pending))
+		}
+
+		b.pending = b.pending[n:]
+	}
+
+	return nil
+}
+
+func (b *batchSink) writeWithRetry(points []Point) error {
+	var err error
+	backoff := b.backoff
+	for attempt := 0; attempt <= b.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		err = b.sink.Write(points)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// coinPoint returns the point for a coin snapshot
+func coinPoint(coin cmc.Coin, t time.Time) Point {
+	return Point{
+		Measurement: "coin",
+		Tags:        map[string]string{"id": coin.ID, "symbol": coin.Symbol},
+		Fields: map[string]float64{
+			"rank":               float64(coin.Rank),
+			"price_usd":          coin.PriceUsd,
+			"price_btc":          coin.PriceBtc,
+			"volume_24h_usd":     coin.Usd24hVolume,
+			"market_cap_usd":     coin.MarketCapUsd,
+			"available_supply":   coin.AvailableSupply,
+			"total_supply":       coin.TotalSupply,
+			"percent_change_1h":  coin.PercentChange1h,
+			"percent_change_24h": coin.PercentChange24h,
+			"percent_change_7d":  coin.PercentChange7d,
+		},
+		Time: t,
+	}
+}
+
+// globalPoint returns the point for a global market snapshot
+func globalPoint(global cmc.GlobalMarketData, t time.Time) Point {
+	return Point{
+		Measurement: "global",
+		Fields: map[string]float64{
+			"total_market_cap_usd":             global.TotalMarketCapUsd,
+			"total_24h_volume_usd":             global.Total24hVolumeUsd,
+			"bitcoin_percentage_of_market_cap": global.BitcoinPercentageOfMarketCap,
+			"active_currencies":                float64(global.ActiveCurrencies),
+			"active_assets":                    float64(global.ActiveAssets),
+			"active_markets":                   float64(global.ActiveMarkets),
+		},
+		Time: t,
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFieldKeys(m map[string]float64) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
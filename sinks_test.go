@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInfluxSinkWrite(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := &InfluxSink{URL: server.URL, Client: server.Client()}
+	err := sink.Write([]Point{{
+		Measurement: "coin price,usd",
+		Tags:        map[string]string{"symbol": "A,B=C D", "id": "bitcoin"},
+		Fields:      map[string]float64{"price usd": 1.5, "rank": 1},
+		Time:        time.Unix(0, 42),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `coin\ price\,usd,id=bitcoin,symbol=A\,B\=C\ D price\ usd=1.5,rank=1 42` + "\n"
+	if body != want {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+}
+
+func TestInfluxSinkWriteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	sink := &InfluxSink{URL: server.URL, Client: server.Client()}
+	err := sink.Write([]Point{{Measurement: "coin", Fields: map[string]float64{"rank": 1}}})
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Errorf("got error %v, want the response body", err)
+	}
+}
+
+func TestGraphiteSinkWrite(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			lines <- nil
+			return
+		}
+		defer conn.Close()
+		var got []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		lines <- got
+	}()
+
+	sink := &GraphiteSink{Address: ln.Addr().String(), Prefix: "crypto"}
+	err = sink.Write([]Point{{
+		Measurement: "coin",
+		Tags:        map[string]string{"id": "bitcoin.cash"},
+		Fields:      map[string]float64{"price_usd": 1.5, "rank 24h": 4},
+		Time:        time.Unix(1500000000, 0),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"crypto.coin.bitcoin_cash.price_usd 1.5 1500000000",
+		"crypto.coin.bitcoin_cash.rank_24h 4 1500000000",
+	}
+	select {
+	case got := <-lines:
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got lines %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out reading from the graphite listener")
+	}
+}
+
+func TestJSONLSinkWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "points.jsonl")
+	sink := &JSONLSink{Path: path}
+
+	for i := 0; i < 2; i++ {
+		err := sink.Write([]Point{{Measurement: "global", Fields: map[string]float64{"active_markets": float64(i)}, Time: time.Unix(int64(i), 0).UTC()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var points []Point
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Point
+		err := json.Unmarshal(scanner.Bytes(), &p)
+		if err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		points = append(points, p)
+	}
+	if len(points) != 2 {
+		t.Fatalf("got %d lines, want 2 appended lines", len(points))
+	}
+	for i, p := range points {
+		if p.Measurement != "global" || p.Fields["active_markets"] != float64(i) {
+			t.Errorf("line %d: got %+v", i, p)
+		}
+	}
+}
+
+// fakeSink records the batches written to it, failing the first fails writes
+type fakeSink struct {
+	fails   int
+	writes  int
+	batches [][]Point
+}
+
+func (s *fakeSink) Write(points []Point) error {
+	s.writes++
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("unavailable")
+	}
+	s.batches = append(s.batches, append([]Point(nil), points...))
+	return nil
+}
+
+// testPoints returns n points numbered by their rank field
+func testPoints(start, n int) []Point {
+	var points []Point
+	for i := start; i < start+n; i++ {
+		points = append(points, Point{Measurement: "coin", Fields: map[string]float64{"rank": float64(i)}})
+	}
+	return points
+}
+
+func ranks(points []Point) []float64 {
+	var r []float64
+	for _, p := range points {
+		r = append(r, p.Fields["rank"])
+	}
+	return r
+}
+
+func TestBatchSinkFlushBatches(t *testing.T) {
+	fake := &fakeSink{}
+	b := &batchSink{name: "fake", sink: fake, batchSize: 2, retries: 1, maxPending: 10}
+
+	err := b.Flush(testPoints(0, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sizes []int
+	for _, batch := range fake.batches {
+		sizes = append(sizes, len(batch))
+	}
+	if !reflect.DeepEqual(sizes, []int{2, 2, 1}) {
+		t.Errorf("got batch sizes %v, want [2 2 1]", sizes)
+	}
+	if len(b.pending) != 0 {
+		t.Errorf("got %d pending points, want 0", len(b.pending))
+	}
+}
+
+func TestBatchSinkRetry(t *testing.T) {
+	fake := &fakeSink{fails: 2}
+	b := &batchSink{name: "fake", sink: fake, batchSize: 10, retries: 2, maxPending: 10}
+
+	err := b.Flush(testPoints(0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.writes != 3 {
+		t.Errorf("got %d writes, want 2 failures and a successful retry", fake.writes)
+	}
+	if len(fake.batches) != 1 || !reflect.DeepEqual(ranks(fake.batches[0]), []float64{0, 1, 2}) {
+		t.Errorf("got batches %v, want the points written once", fake.batches)
+	}
+}
+
+func TestBatchSinkKeepsFailedPoints(t *testing.T) {
+	fake := &fakeSink{fails: 2}
+	b := &batchSink{name: "fake", sink: fake, batchSize: 10, retries: 1, maxPending: 10}
+
+	err := b.Flush(testPoints(0, 2))
+	if err == nil {
+		t.Fatal("got no error, want the failed write's error")
+	}
+
+	// the pending points are sent ahead of the new ones on the next flush
+	err = b.Flush(testPoints(2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.batches) != 1 || !reflect.DeepEqual(ranks(fake.batches[0]), []float64{0, 1, 2}) {
+		t.Errorf("got batches %v, want the pending and new points", fake.batches)
+	}
+}
+
+func TestBatchSinkPendingCap(t *testing.T) {
+	fake := &fakeSink{fails: 1 << 30}
+	b := &batchSink{name: "fake", sink: fake, batchSize: 2, retries: 1, maxPending: 3}
+
+	for i := 0; i < 4; i++ {
+		err := b.Flush(testPoints(i*2, 2))
+		if err == nil {
+			t.Fatal("got no error, want the failed write's error")
+		}
+		if len(b.pending) > b.maxPending {
+			t.Fatalf("got %d pending points, want at most %d", len(b.pending), b.maxPending)
+		}
+	}
+
+	if got := ranks(b.pending); !reflect.DeepEqual(got, []float64{5, 6, 7}) {
+		t.Errorf("got pending points %v, want the newest [5 6 7]", got)
+	}
+}
+
+func TestNewBatchSinkRetries(t *testing.T) {
+	tests := []struct {
+		config []byte
+		want   int
+		err    bool
+	}{
+		{config: []byte(`{"type": "jsonl", "path": "points.jsonl"}`), want: defaultRetries},
+		{config: []byte(`{"type": "jsonl", "path": "points.jsonl", "retries": 0}`), want: 0},
+		{config: []byte(`{"type": "jsonl", "path": "points.jsonl", "retries": 5}`), want: 5},
+		{config: []byte(`{"type": "jsonl", "path": "points.jsonl", "retries": -1}`), err: true},
+	}
+
+	for _, tt := range tests {
+		var config SinkConfig
+		err := json.Unmarshal(tt.config, &config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := newBatchSink(config)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: got no error", tt.config)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: got error %v", tt.config, err)
+			continue
+		}
+		if b.retries != tt.want {
+			t.Errorf("%s: got %d retries, want %d", tt.config, b.retries, tt.want)
+		}
+	}
+}
+
+func TestBatchSinkNoRetries(t *testing.T) {
+	fake := &fakeSink{fails: 1}
+	b := &batchSink{name: "fake", sink: fake, batchSize: 10, retries: 0, maxPending: 10}
+
+	err := b.Flush(testPoints(0, 1))
+	if err == nil {
+		t.Fatal("got no error, want the failed write")
+	}
+	if fake.writes != 1 {
+		t.Errorf("got %d writes, want 1", fake.writes)
+	}
+}
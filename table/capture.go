@@ -0,0 +1,89 @@
+package table
+
+import (
+	"github.com/miguelmota/cryptocharts/capture"
+	gc "github.com/rgburke/goncurses"
+)
+
+// acsRunes maps alternate character set line drawing characters to runes
+var acsRunes = map[rune]rune{
+	'q': '─',
+	'x': '│',
+	'l': '┌',
+	'k': '┐',
+	'm': '└',
+	'j': '┘',
+	't': '├',
+	'u': '┤',
+	'w': '┬',
+	'v': '┴',
+	'n': '┼',
+}
+
+// capture returns the current screen composed from the visible windows
+func (s *Service) capture() *capture.Screen {
+	screen := capture.NewScreen(s.screenCols, s.screenRows)
+
+	windows := []*gc.Window{s.mainwin, s.menuwin, s.helpbarwin, s.logwin}
+	if s.helpVisible {
+		windows = append(windows, s.helpwin)
+	}
+	if s.pickerVisible {
+		windows = append(windows, s.pickerwin)
+	}
+	if s.calcVisible {
+		windows = append(windows, s.calcwin)
+	}
+
+	for _, win := range windows {
+		if win == nil {
+			continue
+		}
+		top, left := win.YX()
+		rows, cols := win.MaxYX()
+		for y := 0; y < rows; y++ {
+			for x := 0; x < cols; x++ {
+				screen.Set(left+x, top+y, s.captureCell(win.MoveInChar(y, x)))
+			}
+		}
+	}
+
+	return screen
+}
+
+// captureCell converts an ncurses character and its attributes to a cell
+func (s *Service) captureCell(ch gc.Char) capture.Cell {
+	r := rune(ch & gc.A_CHARTEXT)
+	if ch&gc.A_ALTCHARSET != 0 {
+		if acs, ok := acsRunes[r]; ok {
+			r = acs
+		}
+	}
+
+	cell := capture.Cell{
+		Ch:        r,
+		Fg:        capture.DefaultColor,
+		Bg:        capture.DefaultColor,
+		Bold:      ch&gc.A_BOLD != 0,
+		Underline: ch&gc.A_UNDERLINE != 0,
+		Reverse:   ch&gc.A_REVERSE != 0,
+	}
+	if pair, ok := s.colorPairs[int16((ch&gc.A_COLOR)>>8)]; ok {
+		cell.Fg, cell.Bg = int(pair[0]), int(pair[1])
+	}
+	return cell
+}
+
+// saveCapture saves the current screen to path, or to a timestamped ANSI
+// file when path is empty
+func (s *Service) saveCapture(path string) error {
+	if path == "" {
+		path = capture.Filename(".ans")
+	}
+	err := capture.Save(s.capture(), path)
+	if err != nil {
+		return err
+	}
+	s.renderPrompt("saved " + path)
+	return nil
+}
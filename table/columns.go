@@ -0,0 +1,345 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	slice "github.com/bradfitz/slice"
+	humanize "github.com/dustin/go-humanize"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+	pad "github.com/willf/pad/utf8"
+)
+
+// column describes a single table column
+type column struct {
+	id       string
+	header   string
+	key      string // sort key shown in brackets in the header
+	name     string // description used in help and the column picker
+	left     bool   // left align instead of right align
+	pinned   bool   // always shown on the left, not scrolled horizontally
+	maxWidth int    // values wider than this are truncated, 0 means no limit
+	minCols  int    // narrowest terminal width the column is shown on
+	desc     bool   // sort descending on first key press
+	value    func(s *Service, coin *cmc.Coin) string
+	less     func(s *Service, a, b *cmc.Coin) bool
+}
+
+// columnGap separates adjacent columns
+const columnGap = " "
+
+// allColumns are the available columns in their default order
+var allColumns = []*column{
+	{
+		id: "rank", header: "[r]ank", key: "r", name: "rank", left: true, pinned: true,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprint(c.Rank) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.Rank < b.Rank },
+	},
+	{
+		id: "name", header: "[n]ame", key: "n", name: "name", left: true, desc: true, pinned: true, maxWidth: 22,
+		value: func(s *Service, c *cmc.Coin) string { return c.Name },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.Name < b.Name },
+	},
+	{
+		id: "symbol", header: "[s]ymbol", key: "s", name: "symbol", left: true,
+		value: func(s *Service, c *cmc.Coin) string { return c.Symbol },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.Symbol < b.Symbol },
+	},
+	{
+		id: "price", header: "[p]rice", key: "p", name: "price", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(c.PriceUsd) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.PriceUsd < b.PriceUsd },
+	},
+	{
+		id: "pricebtc", header: "price [b]tc", key: "b", name: "price in BTC", desc: true, minCols: 100,
+		value: func(s *Service, c *cmc.Coin) string { return strconv.FormatFloat(c.PriceBtc, 'f', 8, 64) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.PriceBtc < b.PriceBtc },
+	},
+	{
+		id: "marketcap", header: "[m]arket cap", key: "m", name: "market cap", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(c.MarketCapUsd) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.MarketCapUsd < b.MarketCapUsd },
+	},
+	{
+		id: "capshare", header: "[%] of cap", key: "%", name: "% of total market cap", desc: true, minCols: 100,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprintf("%.2f%%", s.capShare(c)) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return s.capShare(a) < s.capShare(b) },
+	},
+	{
+		id: "24hvolume", header: "24H [v]olume", key: "v", name: "24 hour volume", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(c.Usd24hVolume) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.Usd24hVolume < b.Usd24hVolume },
+	},
+	{
+		id: "volumecap", header: "v[o]l/cap", key: "o", name: "volume/market cap ratio", desc: true, minCols: 120,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprintf("%.4f", volumeCapRatio(c)) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return volumeCapRatio(a) < volumeCapRatio(b) },
+	},
+	{
+		id: "1hchange", header: "[1]H%", key: "1", name: "1 hour change", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprintf("%.2f%%", c.PercentChange1h) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.PercentChange1h < b.PercentChange1h },
+	},
+	{
+		id: "24hchange", header: "[2]4H%", key: "2", name: "24 hour change", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprintf("%.2f%%", c.PercentChange24h) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.PercentChange24h < b.PercentChange24h },
+	},
+	{
+		id: "7dchange", header: "[7]D%", key: "7", name: "7 day change", desc: true,
+		value: func(s *Service, c *cmc.Coin) string { return fmt.Sprintf("%.2f%%", c.PercentChange7d) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.PercentChange7d < b.PercentChange7d },
+	},
+	{
+		id: "totalsupply", header: "[t]otal supply", key: "t", name: "total supply", desc: true, minCols: 140,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(c.TotalSupply) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.TotalSupply < b.TotalSupply },
+	},
+	{
+		id: "availablesupply", header: "[a]vailable supply", key: "a", name: "available supply", desc: true, minCols: 140,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(c.AvailableSupply) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return a.AvailableSupply < b.AvailableSupply },
+	},
+	{
+		id: "fdv", header: "[f]ully diluted", key: "f", name: "fully diluted value", desc: true, minCols: 140,
+		value: func(s *Service, c *cmc.Coin) string { return humanize.Commaf(fullyDilutedValue(c)) },
+		less:  func(s *Service, a, b *cmc.Coin) bool { return fullyDilutedValue(a) < fullyDilutedValue(b) },
+	},
+	{
+		id: "lastupdated", header: "[l]ast updated", key: "l", name: "last updated", desc: true, minCols: 120,
+		value: func(s *Service, c *cmc.Coin) string {
+			return time.Unix(lastUpdated(c), 0).Format("15:04:05 Jan 02")
+		},
+		less: func(s *Service, a, b *cmc.Coin) bool { return lastUpdated(a) < lastUpdated(b) },
+	},
+}
+
+// DefaultColumns are the columns shown when none are configured
+var DefaultColumns = []string{
+	"rank",
+	"name",
+	"symbol",
+	"price",
+	"marketcap",
+	"24hvolume",
+	"1hchange",
+	"24hchange",
+	"7dchange",
+	"totalsupply",
+	"availablesupply",
+	"lastupdated",
+}
+
+// ColumnIDs returns the ids of all available columns
+func ColumnIDs() []string {
+	var ids []string
+	for _, col := range allColumns {
+		ids = append(ids, col.id)
+	}
+	return ids
+}
+
+func findColumn(id string) *column {
+	for _, col := range allColumns {
+		if col.id == id {
+			return col
+		}
+	}
+	return nil
+}
+
+// ValidateColumns returns an error listing the available columns when one of
+// the column ids is unknown
+func ValidateColumns(ids []string) error {
+	for _, id := range ids {
+		if findColumn(id) == nil {
+			return fmt.Errorf("unknown column %q, available columns: %s", id, strings.Join(ColumnIDs(), " | "))
+		}
+	}
+	return nil
+}
+
+// setColumns sets the visible columns in the given order, hidden columns are
+// kept after them so they can be re-enabled from the column picker
+func (s *Service) setColumns(ids []string) error {
+	if len(ids) == 0 {
+		ids = DefaultColumns
+	}
+
+	err := ValidateColumns(ids)
+	if err != nil {
+		return err
+	}
+
+	s.columnOrder = []*column{}
+	s.columnVisible = map[string]bool{}
+	for _, id := range ids {
+		col := findColumn(id)
+		if s.columnVisible[id] {
+			continue
+		}
+		s.columnOrder = append(s.columnOrder, col)
+		s.columnVisible[id] = true
+	}
+
+	for _, col := range allColumns {
+		if !s.columnVisible[col.id] {
+			s.columnOrder = append(s.columnOrder, col)
+		}
+	}
+
+	return nil
+}
+
+// visibleColumns returns the visible columns in display order
+func (s *Service) visibleColumns() []*column {
+	var cols []*column
+	for _, col := range s.columnOrder {
+		if s.columnVisible[col.id] {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// columnByKey returns the visible column sorted by the given key
+func (s *Service) columnByKey(key string) *column {
+	for _, col := range s.visibleColumns() {
+		if col.key == key {
+			return col
+		}
+	}
+	return nil
+}
+
+// SortCoins sorts the coins by the column with the given id, the total market
+// cap is used for the % of total market cap column
+func SortCoins(coins []*cmc.Coin, id string, desc bool, totalMarketCap float64) error {
+	col := findColumn(id)
+	if col == nil {
+		return fmt.Errorf("unknown column %q, available columns: %v", id, ColumnIDs())
+	}
+
+	s := &Service{totalMarketCap: totalMarketCap}
+	s.sortCoins(coins, col, desc)
+	return nil
+}
+
+func (s *Service) sortCoins(coins []*cmc.Coin, col *column, desc bool) {
+	slice.Sort(coins[:], func(i, j int) bool {
+		if desc == true {
+			i, j = j, i
+		}
+		return col.less(s, coins[i], coins[j])
+	})
+}
+
+// layoutColumns returns the pinned columns and the scrollable columns that
+// fit on screen at the current horizontal scroll offset, and the cell text
+// and width of every visible column
+func (s *Service) layoutColumns() ([]*column, []*column, map[string][]string, map[string]int) {
+	var pinned, scrollable []*column
+	for _, col := range s.visibleColumns() {
+		if col.minCols > s.screenCols {
+			continue
+		}
+		if col.pinned {
+			pinned = append(pinned, col)
+		} else {
+			scrollable = append(scrollable, col)
+		}
+	}
+
+	values := map[string][]string{}
+	widths := map[string]int{}
+	for _, col := range append(pinned, scrollable...) {
+		width := utf8.RuneCountInString(col.header)
+		cells := make([]string, len(s.rows))
+		for i, coin := range s.rows {
+			cells[i] = col.value(s, coin)
+			if n := utf8.RuneCountInString(cells[i]); n > width {
+				width = n
+			}
+		}
+		if col.maxWidth > 0 && width > col.maxWidth {
+			width = col.maxWidth
+		}
+		values[col.id] = cells
+		widths[col.id] = width
+	}
+
+	if s.scrollCol > len(scrollable)-1 {
+		s.scrollCol = len(scrollable) - 1
+	}
+	if s.scrollCol < 0 {
+		s.scrollCol = 0
+	}
+
+	used := 0
+	for _, col := range pinned {
+		used += widths[col.id] + len(columnGap)
+	}
+
+	// leave room for the scroll markers at the end of the header
+	budget := s.screenCols - 2
+
+	var shown []*column
+	s.scrollEnd = len(scrollable) == 0
+	for i, col := range scrollable {
+		if i < s.scrollCol {
+			continue
+		}
+		used += widths[col.id] + len(columnGap)
+		if used > budget && len(shown) > 0 {
+			break
+		}
+		shown = append(shown, col)
+		if i == len(scrollable)-1 {
+			s.scrollEnd = true
+		}
+	}
+
+	return pinned, shown, values, widths
+}
+
+// padCell truncates or pads the cell text to the column width
+func padCell(col *column, str string, width int) string {
+	runes := []rune(str)
+	if len(runes) > width {
+		str = string(runes[:width])
+	}
+	if col.left {
+		return pad.Right(str, width, " ")
+	}
+	return pad.Left(str, width, " ")
+}
+
+// capShare returns the coin's percentage of the total market cap
+func (s *Service) capShare(coin *cmc.Coin) float64 {
+	if s.totalMarketCap == 0 {
+		return 0
+	}
+	return coin.MarketCapUsd / s.totalMarketCap * 100
+}
+
+func volumeCapRatio(coin *cmc.Coin) float64 {
+	if coin.MarketCapUsd == 0 {
+		return 0
+	}
+	return coin.Usd24hVolume / coin.MarketCapUsd
+}
+
+// fullyDilutedValue uses total supply since the API doesn't report max supply
+func fullyDilutedValue(coin *cmc.Coin) float64 {
+	return coin.PriceUsd * coin.TotalSupply
+}
+
+// lastUpdated returns the coin's last updated unix timestamp, which the API
+// reports as a string
+func lastUpdated(coin *cmc.Coin) int64 {
+	unix, _ := strconv.ParseInt(coin.LastUpdated, 10, 64)
+	return unix
+}
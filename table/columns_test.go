@@ -0,0 +1,88 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+func columnIDs(cols []*column) []string {
+	ids := []string{}
+	for _, col := range cols {
+		ids = append(ids, col.id)
+	}
+	return ids
+}
+
+func TestLayoutColumns(t *testing.T) {
+	coin := &cmc.Coin{
+		Rank:         1,
+		Name:         "Bitcoin",
+		Symbol:       "BTC",
+		PriceUsd:     10000,
+		PriceBtc:     1,
+		MarketCapUsd: 1e9,
+		TotalSupply:  21e6,
+	}
+	columns := []string{"rank", "name", "symbol", "price", "pricebtc", "marketcap", "capshare", "volumecap", "totalsupply"}
+
+	tests := []struct {
+		screenCols int
+		scrollCol  int
+		shown      []string
+		scrollEnd  bool
+	}{
+		// pricebtc, capshare, volumecap and totalsupply need a wider terminal
+		{screenCols: 60, shown: []string{"symbol", "price", "marketcap"}, scrollEnd: true},
+		{screenCols: 100, shown: []string{"symbol", "price", "pricebtc", "marketcap", "capshare"}, scrollEnd: true},
+		{screenCols: 140, shown: []string{"symbol", "price", "pricebtc", "marketcap", "capshare", "volumecap", "totalsupply"}, scrollEnd: true},
+		// columns that don't fit are left for scrolling
+		{screenCols: 30, shown: []string{"symbol"}},
+		// the first scrollable column is shown even when only the pinned fit
+		{screenCols: 10, shown: []string{"symbol"}},
+		{screenCols: 30, scrollCol: 1, shown: []string{"price"}},
+		{screenCols: 60, scrollCol: 1, shown: []string{"price", "marketcap"}, scrollEnd: true},
+		// scrolling past the last column stops at it
+		{screenCols: 60, scrollCol: 10, shown: []string{"marketcap"}, scrollEnd: true},
+	}
+
+	for _, tt := range tests {
+		s := &Service{screenCols: tt.screenCols, scrollCol: tt.scrollCol, rows: []*cmc.Coin{coin}}
+		err := s.setColumns(columns)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pinned, shown, values, widths := s.layoutColumns()
+		if got := columnIDs(pinned); !reflect.DeepEqual(got, []string{"rank", "name"}) {
+			t.Errorf("%d cols scrolled %d got pinned %v, want [rank name]", tt.screenCols, tt.scrollCol, got)
+		}
+		if got := columnIDs(shown); !reflect.DeepEqual(got, tt.shown) {
+			t.Errorf("%d cols scrolled %d got columns %v, want %v", tt.screenCols, tt.scrollCol, got, tt.shown)
+		}
+		if s.scrollEnd != tt.scrollEnd {
+			t.Errorf("%d cols scrolled %d got scroll end %v, want %v", tt.screenCols, tt.scrollCol, s.scrollEnd, tt.scrollEnd)
+		}
+		if got := values["name"]; !reflect.DeepEqual(got, []string{"Bitcoin"}) {
+			t.Errorf("%d cols got name cells %q, want [Bitcoin]", tt.screenCols, got)
+		}
+		if widths["name"] != len("Bitcoin") || widths["marketcap"] != len("1,000,000,000") {
+			t.Errorf("%d cols got widths %v, want the widest cell or header", tt.screenCols, widths)
+		}
+	}
+}
+
+func TestLayoutColumnsMaxWidth(t *testing.T) {
+	coin := &cmc.Coin{Rank: 1, Name: "A Very Long Coin Name That Goes On", Symbol: "LONG"}
+	s := &Service{screenCols: 80, rows: []*cmc.Coin{coin}}
+	err := s.setColumns([]string{"rank", "name", "symbol"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, _, widths := s.layoutColumns()
+	if widths["name"] != 22 {
+		t.Errorf("got name width %d, want the max width 22", widths["name"])
+	}
+}
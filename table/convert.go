@@ -0,0 +1,128 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/miguelmota/cryptocharts/data"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+	gc "github.com/rgburke/goncurses"
+)
+
+func (s *Service) toggleCalculator() {
+	s.calcVisible = !s.calcVisible
+	if s.calcVisible && s.calcConverted == "" {
+		s.convertCalculatorInput(s.calcInput)
+	}
+	s.renderCalculator()
+}
+
+// handleCalculatorKey handles key presses while the calculator is open
+func (s *Service) handleCalculatorKey(ch gc.Key) {
+	chstr := fmt.Sprint(ch)
+	switch {
+	case chstr == "27": // esc
+		s.toggleCalculator()
+		return
+	case ch == gc.KEY_RETURN, ch == gc.KEY_ENTER:
+		s.convertCalculatorInput(s.calcInput)
+	case ch == gc.KEY_BACKSPACE, chstr == "127", chstr == "8":
+		if len(s.calcInput) > 0 {
+			s.calcInput = s.calcInput[:len(s.calcInput)-1]
+		}
+	case chstr == "21": // ctrl-u
+		s.calcInput = ""
+	default:
+		if ch >= 32 && ch < 127 {
+			s.calcInput = fmt.Sprintf("%s%c", s.calcInput, rune(ch))
+		}
+	}
+	s.renderCalculator()
+}
+
+// convertCalculatorInput converts the calculator input between the loaded
+// coins or fiat currencies. Inputs are only converted on enter since
+// converting can fetch coins and rates
+func (s *Service) convertCalculatorInput(input string) {
+	if s.converter == nil {
+		return
+	}
+
+	result, err := s.converter.ConvertInput(input)
+	if err != nil {
+		result = err.Error()
+	}
+	s.calcConverted, s.calcResult = input, result
+}
+
+// calculatorResult returns the result of the calculator input, once it's
+// converted
+func (s *Service) calculatorResult() string {
+	switch {
+	case s.calcConverted == "":
+		return "loading..."
+	case s.calcConverted != s.calcInput:
+		return "<enter> to convert"
+	}
+	return s.calcResult
+}
+
+// setConverter rebuilds the calculator's converter from the loaded coins
+func (s *Service) setConverter() {
+	coins := make([]cmc.Coin, len(s.coins))
+	for i, coin := range s.coins {
+		coins[i] = *coin
+	}
+	s.converter = data.NewConverter(coins)
+
+	// convert the last converted input again with the new prices
+	if s.calcConverted != "" {
+		s.convertCalculatorInput(s.calcConverted)
+	}
+}
+
+func (s *Service) renderCalculator() error {
+	if !s.calcVisible {
+		if s.calcwin != nil {
+			s.calcwin.ClearOk(true)
+			s.calcwin.Clear()
+			s.calcwin.SetBackground(gc.ColorPair(6))
+			s.calcwin.ColorOn(6)
+			s.calcwin.Resize(0, 0)
+			s.calcwin.MoveWindow(200, 200)
+			s.calcwin.Refresh()
+			s.renderMenu()
+		}
+		return nil
+	}
+
+	height, width := 6, 50
+	if width > s.screenCols {
+		width = s.screenCols
+	}
+
+	var err error
+	if s.calcwin == nil {
+		s.calcwin, err = gc.NewWindow(height, width, (s.screenRows/2)-(height/2), (s.screenCols/2)-(width/2))
+		if err != nil {
+			return err
+		}
+	}
+
+	result := s.calculatorResult()
+	if len(result) > width-4 {
+		result = result[:width-4]
+	}
+
+	s.calcwin.Clear()
+	s.calcwin.SetBackground(gc.ColorPair(1))
+	s.calcwin.ColorOn(1)
+	s.calcwin.Resize(height, width)
+	s.calcwin.MoveWindow((s.screenRows/2)-(height/2), (s.screenCols/2)-(width/2))
+	s.calcwin.Box(0, 0)
+	s.calcwin.MovePrint(0, 1, "Convert")
+	s.calcwin.MovePrint(1, 1, fmt.Sprintf("> %s_", s.calcInput))
+	s.calcwin.MovePrint(2, 1, fmt.Sprintf("= %s", result))
+	s.calcwin.MovePrint(4, 1, "<amount> <from> <to>, <esc> to close")
+	s.calcwin.Refresh()
+	return nil
+}
@@ -0,0 +1,102 @@
+package table
+
+import (
+	gc "github.com/rgburke/goncurses"
+)
+
+// mouseWheelDown is BUTTON5_PRESSED in the ncurses 6 mouse mask, which
+// goncurses has no constant for. The wheel is reported as buttons 4 and 5
+const mouseWheelDown gc.MouseButton = 0x200000
+
+// wheelRows is the number of rows moved per scroll wheel step
+const wheelRows = 3
+
+// headerSpan is the screen columns of a header cell
+type headerSpan struct {
+	start, end int
+	column     *column
+	scroll     int // -1 or 1 for the column scroll markers
+}
+
+// enableMouse reports button presses and the scroll wheel as KEY_MOUSE,
+// without a click interval so presses aren't delayed
+func (s *Service) enableMouse() {
+	gc.MouseMask(gc.M_ALL, nil)
+	gc.MouseInterval(0)
+}
+
+// handleMouse sorts by a clicked header, selects a clicked row and moves the
+// selection with the scroll wheel
+func (s *Service) handleMouse() {
+	e := gc.GetMouse()
+	if e == nil || s.helpVisible {
+		return
+	}
+
+	switch {
+	case e.State&gc.M_B4_PRESSED != 0:
+		s.moveCurrentItem(-wheelRows)
+	case e.State&mouseWheelDown != 0:
+		s.moveCurrentItem(wheelRows)
+	case e.State&gc.M_B1_PRESSED != 0:
+		if e.Y == 0 {
+			s.clickHeader(e.X)
+		} else if e.Y <= s.menuHeight {
+			s.selectItem(s.menuTop + e.Y - 1)
+		}
+	}
+}
+
+// clickHeader sorts by the header cell at the screen column, or scrolls the
+// columns when a scroll marker is clicked
+func (s *Service) clickHeader(x int) {
+	for _, span := range s.headerSpans {
+		if x < span.start || x >= span.end {
+			continue
+		}
+		if span.scroll != 0 {
+			s.handleScroll(span.scroll)
+		} else {
+			s.handleSort(span.column.id, span.column.desc)
+		}
+		return
+	}
+}
+
+// moveCurrentItem moves the selection by delta rows
+func (s *Service) moveCurrentItem(delta int) {
+	s.selectItem(s.currentItem + delta)
+	s.loadMoreIfNeeded()
+}
+
+// selectItem selects the menu item at the index
+func (s *Service) selectItem(idx int) {
+	if idx >= len(s.menuItems) {
+		idx = len(s.menuItems) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if len(s.menuItems) == 0 {
+		return
+	}
+	s.currentItem = idx
+	s.menu.Current(s.menuItems[s.currentItem])
+	s.syncMenuTop()
+}
+
+// syncMenuTop tracks the first visible menu row, which the menu scrolls just
+// far enough to keep the current item visible
+func (s *Service) syncMenuTop() {
+	current := s.menu.Current(nil)
+	if current == nil {
+		return
+	}
+	idx := current.Index()
+	if idx < s.menuTop {
+		s.menuTop = idx
+	}
+	if s.menuHeight > 0 && idx >= s.menuTop+s.menuHeight {
+		s.menuTop = idx - s.menuHeight + 1
+	}
+}
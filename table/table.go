@@ -5,56 +5,84 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
-	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
-	slice "github.com/bradfitz/slice"
-	humanize "github.com/dustin/go-humanize"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/theme"
 	cmc "github.com/miguelmota/go-coinmarketcap"
 	gc "github.com/rgburke/goncurses"
-	pad "github.com/willf/pad/utf8"
 )
 
 var wg sync.WaitGroup
 
 // Service service struct
 type Service struct {
-	stdsrc        *gc.Window
-	screenRows    int
-	screenCols    int
-	mainwin       *gc.Window
-	menuwin       *gc.Window
-	menuwinWidth  int
-	menuwinHeight int
-	menusubwin    *gc.Window
-	helpbarwin    *gc.Window
-	helpwin       *gc.Window
-	helpVisible   bool
-	logwin        *gc.Window
-	menu          *gc.Menu
-	menuItems     []*gc.MenuItem
-	menuData      []string
-	menuHeader    string
-	menuWidth     int
-	menuHeight    int
-	coins         []*cmc.Coin
-	sortBy        string
-	sortDesc      bool
-	limit         uint
-	refresh       uint
-	primaryColor  string
-	lastLog       string
-	currentItem   int
+	stdsrc         *gc.Window
+	screenRows     int
+	screenCols     int
+	mainwin        *gc.Window
+	menuwin        *gc.Window
+	menuwinWidth   int
+	menuwinHeight  int
+	menusubwin     *gc.Window
+	helpbarwin     *gc.Window
+	helpwin        *gc.Window
+	helpVisible    bool
+	logwin         *gc.Window
+	menu           *gc.Menu
+	menuItems      []*gc.MenuItem
+	menuData       []string
+	menuHeader     string
+	menuWidth      int
+	menuHeight     int
+	coins          []*cmc.Coin
+	sortBy         string
+	sortDesc       bool
+	limit          uint
+	refresh        uint
+	theme          theme.Theme
+	depth          int
+	lastLog        string
+	currentItem    int
+	columns        []string
+	columnOrder    []*column
+	columnVisible  map[string]bool
+	pickerwin      *gc.Window
+	pickerVisible  bool
+	pickerItem     int
+	totalMarketCap float64
+	scrollCol      int
+	scrollEnd      bool
+	rows           []*cmc.Coin
+	totalCoins     int
+	loadAll        bool
+	filter         string
+	filterPages    int // pages loaded while the filter was set
+	colorPairs     map[int16][2]int16
+	calcwin        *gc.Window
+	calcVisible    bool
+	calcInput      string
+	calcConverted  string // the last converted calculator input
+	calcResult     string // the result of the converted input
+	converter      *data.Converter
+	capturePath    string
+	menuTop        int
+	headerSpans    []headerSpan
 }
 
 // Options options struct
 type Options struct {
-	Color   string
+	Theme   theme.Theme
+	Depth   int // number of colors the terminal supports, see theme.Depth
 	Limit   uint
-	Refresh uint
+	Refresh uint // seconds between refreshes
+	Columns []string
+	LoadAll bool
+	Capture string // file to save the first rendered screen to before exiting
 }
 
 var once sync.Once
@@ -64,9 +92,14 @@ func New(opts *Options) *Service {
 	var instance *Service
 	//	once.Do(func() {
 	instance = &Service{}
-	instance.primaryColor = opts.Color
+	instance.theme = opts.Theme
+	instance.depth = opts.Depth
 	instance.limit = opts.Limit
 	instance.refresh = opts.Refresh
+	instance.columns = opts.Columns
+	instance.loadAll = opts.LoadAll
+	instance.capturePath = opts.Capture
+	instance.calcInput = "1 btc usd"
 	//	})
 
 	return instance
@@ -74,7 +107,11 @@ func New(opts *Options) *Service {
 
 // Render starts GUI
 func (s *Service) Render() error {
-	var err error
+	err := s.setColumns(s.columns)
+	if err != nil {
+		return err
+	}
+
 	s.stdsrc, err = gc.Init()
 	defer gc.End()
 	if err != nil {
@@ -87,6 +124,7 @@ func (s *Service) Render() error {
 	gc.Echo(false)
 	gc.Cursor(0)
 	s.stdsrc.Keypad(true)
+	s.enableMouse()
 	cols, rows := GetScreenSize()
 	s.screenRows = rows
 	s.screenCols = cols
@@ -99,7 +137,7 @@ func (s *Service) Render() error {
 	}
 
 	go func() {
-		ticker := time.NewTicker(time.Duration(int64(s.refresh)) * time.Minute)
+		ticker := time.NewTicker(time.Duration(int64(s.refresh)) * time.Second)
 		for {
 			select {
 			case <-ticker.C:
@@ -107,10 +145,12 @@ func (s *Service) Render() error {
 				//s.menuwin.Refresh()
 				s.fetchData()
 				s.setMenuData()
+				s.renderHelpBar()
 				err := s.renderMenu()
 				if err != nil {
 					panic(err)
 				}
+				s.renderCalculator()
 			}
 		}
 	}()
@@ -132,6 +172,10 @@ func (s *Service) Render() error {
 	s.renderHelpBar()
 	s.renderHelpWindow()
 
+	if s.capturePath != "" {
+		return s.saveCapture(s.capturePath)
+	}
+
 	//stdsrc.GetChar() // required so it doesn't exit
 	//wg.Wait()
 
@@ -153,16 +197,28 @@ func (s *Service) Render() error {
 	form.Driver(gc.REQ_FIRST_FIELD)
 
 	for {
+		s.syncMenuTop()
 		gc.Update()
 		ch := s.menuwin.GetChar()
 		chstr := fmt.Sprint(ch)
 		//s.log(fmt.Sprint(ch))
+		if s.pickerVisible {
+			s.handlePickerKey(ch)
+			continue
+		}
+		if s.calcVisible {
+			s.handleCalculatorKey(ch)
+			continue
+		}
 		switch {
+		case ch == gc.KEY_MOUSE:
+			s.handleMouse()
 		case ch == gc.KEY_DOWN, chstr == "106": // "j"
 			if s.currentItem < len(s.menuItems)-1 {
 				s.currentItem = s.currentItem + 1
 				s.menu.Current(s.menuItems[s.currentItem])
 			}
+			s.loadMoreIfNeeded()
 
 			form.Driver(gc.REQ_NEXT_FIELD)
 			form.Driver(gc.REQ_END_LINE)
@@ -181,30 +237,10 @@ func (s *Service) Render() error {
 				}
 			}
 			s.menu.Driver(gc.REQ_TOGGLE)
-		case chstr == "114": // "r"
-			s.handleSort("rank", false)
-		case chstr == "110": // "n"
-			s.handleSort("name", true)
-		case chstr == "115": // "s"
-			s.handleSort("symbol", false)
-		case chstr == "112": // "p
-			s.handleSort("price", true)
-		case chstr == "109": // "m
-			s.handleSort("marketcap", true)
-		case chstr == "118": // "v
-			s.handleSort("24hvolume", true)
-		case chstr == "49": // "1"
-			s.handleSort("1hchange", true)
-		case chstr == "50": // "2"
-			s.handleSort("24hchange", true)
-		case chstr == "55": // "7"
-			s.handleSort("7dchange", true)
-		case chstr == "116": // "t"
-			s.handleSort("totalsupply", true)
-		case chstr == "97": // "a"
-			s.handleSort("availablesupply", true)
-		case chstr == "108": // "l"
-			s.handleSort("lastupdated", true)
+		case ch == gc.KEY_LEFT:
+			s.handleScroll(-1)
+		case ch == gc.KEY_RIGHT:
+			s.handleScroll(1)
 		case chstr == "21": // ctrl-u
 			s.currentItem = s.currentItem - s.menuHeight
 			if s.currentItem < 0 {
@@ -225,8 +261,22 @@ func (s *Service) Render() error {
 			}
 			//s.log(fmt.Sprintf("%v %v", s.currentItem, s.screenRows))
 			s.menu.Current(s.menuItems[s.currentItem])
+			s.loadMoreIfNeeded()
+		case chstr == "65": // "A"
+			s.handleLoadAll()
+		case chstr == "47": // "/"
+			s.promptFilter()
 		case chstr == "104", chstr == "63": // "h", "?"
 			s.toggleHelp()
+		case chstr == "99": // "c"
+			s.togglePicker()
+		case chstr == "120": // "x"
+			s.toggleCalculator()
+		case chstr == "83": // "S"
+			err := s.saveCapture("")
+			if err != nil {
+				s.renderPrompt(err.Error())
+			}
 		case chstr == "3", chstr == "113", chstr == "27": // ctrl-c, "q", esc
 			if s.helpVisible && chstr == "27" {
 				s.toggleHelp()
@@ -235,29 +285,177 @@ func (s *Service) Render() error {
 				return nil
 			}
 		default:
-			s.menu.Driver(gc.DriverActions[ch])
+			if col := s.columnByKey(string(rune(ch))); col != nil {
+				s.handleSort(col.id, col.desc)
+			} else {
+				s.menu.Driver(gc.DriverActions[ch])
+			}
 		}
 	}
 }
 
+// fetchData refetches all of the loaded coins
 func (s *Service) fetchData() error {
-	coins, err := cmc.GetAllCoinData(int(s.limit))
+	limit := int(s.limit)
+	if len(s.coins) > limit {
+		limit = len(s.coins)
+	}
+	if s.loadAll {
+		limit = 0
+	}
+
+	coins, err := data.FetchCoins(0, limit)
 	if err != nil {
 		return err
 	}
 
 	s.coins = []*cmc.Coin{}
+	s.totalMarketCap = 0
 	for i := range coins {
 		coin := coins[i]
 		s.coins = append(s.coins, &coin)
+		s.totalMarketCap += coin.MarketCapUsd
 	}
 
+	// prefer the global totals over the loaded coins
+	marketData, err := cmc.GetMarketData()
+	if err == nil {
+		if marketData.TotalMarketCapUsd > 0 {
+			s.totalMarketCap = marketData.TotalMarketCapUsd
+		}
+		s.totalCoins = marketData.ActiveCurrencies + marketData.ActiveAssets
+	}
+	if s.totalCoins < len(s.coins) || s.loadAll {
+		s.totalCoins = len(s.coins)
+	}
+	s.setConverter()
+
 	return nil
 }
 
+// loadMore fetches the next page of coins, or all remaining coins when all
+// is true
+func (s *Service) loadMore(all bool) error {
+	limit := int(s.limit)
+	if all {
+		limit = 0
+	}
+
+	s.log("loading...")
+	coins, err := data.FetchCoins(len(s.coins), limit)
+	if err != nil {
+		s.log("loading failed")
+		return err
+	}
+
+	loaded := map[string]bool{}
+	for _, coin := range s.coins {
+		loaded[coin.ID] = true
+	}
+	for i := range coins {
+		coin := coins[i]
+		if loaded[coin.ID] {
+			continue
+		}
+		s.coins = append(s.coins, &coin)
+	}
+	s.setConverter()
+
+	if len(coins) == 0 || all || len(s.coins) > s.totalCoins {
+		s.totalCoins = len(s.coins)
+	}
+	s.log("")
+
+	s.setMenuData()
+	s.renderHelpBar()
+	return s.renderMenu()
+}
+
+// maxFilterPages is how many pages are loaded looking for more matches of a
+// filter, since the filtered rows can end well before the loaded coins do
+const maxFilterPages = 3
+
+// loadMoreIfNeeded loads the next page once the cursor is within a page of
+// the end of the loaded coins. While filtering, a page is only loaded at the
+// last matching row, up to maxFilterPages for each filter
+func (s *Service) loadMoreIfNeeded() {
+	if s.loadAll || len(s.coins) >= s.totalCoins {
+		return
+	}
+	if s.filter != "" {
+		if s.currentItem < len(s.menuItems)-1 || s.filterPages >= maxFilterPages {
+			return
+		}
+		s.filterPages++
+	} else if s.currentItem < len(s.menuItems)-s.menuHeight {
+		return
+	}
+	s.loadMore(false)
+}
+
+// handleLoadAll loads every listed coin
+func (s *Service) handleLoadAll() {
+	if s.loadAll {
+		return
+	}
+	s.loadAll = true
+	s.loadMore(true)
+}
+
+// promptFilter reads a filter in the help bar, rows are filtered by name or
+// symbol as it's typed
+func (s *Service) promptFilter() {
+	for {
+		s.renderPrompt(fmt.Sprintf("/%s", s.filter))
+		ch := s.menuwin.GetChar()
+		chstr := fmt.Sprint(ch)
+		switch {
+		case ch == gc.KEY_RETURN, ch == gc.KEY_ENTER:
+			s.renderHelpBar()
+			return
+		case chstr == "27": // esc
+			s.filter = ""
+			s.applyFilter()
+			s.renderHelpBar()
+			return
+		case ch == gc.KEY_BACKSPACE, chstr == "127", chstr == "8":
+			if len(s.filter) > 0 {
+				runes := []rune(s.filter)
+				s.filter = string(runes[:len(runes)-1])
+				s.applyFilter()
+			}
+		default:
+			if ch >= 32 && ch < 127 {
+				s.filter = fmt.Sprintf("%s%c", s.filter, rune(ch))
+				s.applyFilter()
+			}
+		}
+	}
+}
+
+func (s *Service) applyFilter() {
+	s.currentItem = 0
+	s.filterPages = 0
+	s.setMenuData()
+	s.renderMenu()
+}
+
+// matchesFilter reports whether the coin matches the current filter
+func (s *Service) matchesFilter(coin *cmc.Coin) bool {
+	if s.filter == "" {
+		return true
+	}
+	filter := strings.ToLower(s.filter)
+	return strings.Contains(strings.ToLower(coin.Name), filter) ||
+		strings.Contains(strings.ToLower(coin.Symbol), filter)
+}
+
 func (s *Service) handleClick(idx int) {
-	slug := strings.ToLower(strings.Replace(s.coins[idx].Name, " ", "-", -1))
-	exec.Command("open", fmt.Sprintf("https://coinmarketcap.com/currencies/%s", slug)).Output()
+	if idx >= len(s.rows) {
+		return
+	}
+	// the coin id is its CoinMarketCap slug
+	exec.Command("open", fmt.Sprintf("https://coinmarketcap.com/currencies/%s", s.rows[idx].ID)).Output()
 }
 
 func (s *Service) handleSort(name string, desc bool) {
@@ -274,115 +472,129 @@ func (s *Service) handleSort(name string, desc bool) {
 	}
 }
 
+// handleScroll scrolls the unpinned columns horizontally
+func (s *Service) handleScroll(delta int) {
+	if delta > 0 && s.scrollEnd {
+		return
+	}
+	s.scrollCol += delta
+	s.setMenuData()
+	err := s.renderMenu()
+	if err != nil {
+		panic(err)
+	}
+}
+
 func (s *Service) setMenuData() {
-	slice.Sort(s.coins[:], func(i, j int) bool {
-		if s.sortDesc == true {
-			i, j = j, i
-		}
-		switch s.sortBy {
-		case "rank":
-			return s.coins[i].Rank < s.coins[j].Rank
-		case "name":
-			return s.coins[i].Name < s.coins[j].Name
-		case "symbol":
-			return s.coins[i].Symbol < s.coins[j].Symbol
-		case "price":
-			return s.coins[i].PriceUsd < s.coins[j].PriceUsd
-		case "marketcap":
-			return s.coins[i].MarketCapUsd < s.coins[j].MarketCapUsd
-		case "24hvolume":
-			return s.coins[i].Usd24hVolume < s.coins[j].Usd24hVolume
-		case "1hchange":
-			return s.coins[i].PercentChange1h < s.coins[j].PercentChange1h
-		case "24hchange":
-			return s.coins[i].PercentChange24h < s.coins[j].PercentChange24h
-		case "7dchange":
-			return s.coins[i].PercentChange7d < s.coins[j].PercentChange7d
-		case "totalsupply":
-			return s.coins[i].TotalSupply < s.coins[j].TotalSupply
-		case "availablesupply":
-			return s.coins[i].AvailableSupply < s.coins[j].AvailableSupply
-		case "lastupdated":
-			return s.coins[i].LastUpdated < s.coins[j].LastUpdated
-		default:
-			return s.coins[i].Rank < s.coins[j].Rank
+	sortCol := findColumn(s.sortBy)
+	if sortCol == nil || !s.columnVisible[sortCol.id] {
+		s.sortBy = "rank"
+		sortCol = findColumn(s.sortBy)
+	}
+
+	s.sortCoins(s.coins, sortCol, s.sortDesc)
+
+	s.rows = []*cmc.Coin{}
+	for _, coin := range s.coins {
+		if s.matchesFilter(coin) {
+			s.rows = append(s.rows, coin)
 		}
-	})
+	}
+
+	pinned, scrollable, values, widths := s.layoutColumns()
+	cols := append(pinned, scrollable...)
 
 	var menuData []string
-	for _, coin := range s.coins {
-		unix, _ := strconv.ParseInt(coin.LastUpdated, 10, 64)
-		lastUpdated := time.Unix(unix, 0).Format("15:04:05 Jan 02")
-		fields := []string{
-			pad.Right(fmt.Sprint(coin.Rank), 4, " "),
-			pad.Right(coin.Name, 22, " "),
-			pad.Right(coin.Symbol, 6, " "),
-			pad.Left(humanize.Commaf(coin.PriceUsd), 12, " "),
-			pad.Left(humanize.Commaf(coin.MarketCapUsd), 17, " "),
-			pad.Left(humanize.Commaf(coin.Usd24hVolume), 15, " "),
-			pad.Left(fmt.Sprintf("%.2f%%", coin.PercentChange1h), 9, " "),
-			pad.Left(fmt.Sprintf("%.2f%%", coin.PercentChange24h), 9, " "),
-			pad.Left(fmt.Sprintf("%.2f%%", coin.PercentChange7d), 9, " "),
-			pad.Left(humanize.Commaf(coin.TotalSupply), 20, " "),
-			pad.Left(humanize.Commaf(coin.AvailableSupply), 18, " "),
-			pad.Left(fmt.Sprintf("%s", lastUpdated), 18, " "),
-			// add %percent of cap
-		}
-		var str string
-		for _, f := range fields {
-			str = fmt.Sprintf("%s%s", str, f)
-		}
-		menuData = append(menuData, str)
+	for i := range s.rows {
+		var cells []string
+		for _, col := range cols {
+			cells = append(cells, padCell(col, values[col.id][i], widths[col.id]))
+		}
+		menuData = append(menuData, strings.Join(cells, columnGap))
+	}
+
+	if len(menuData) == 0 {
+		menuData = append(menuData, "no matches")
 	}
 
 	s.menuData = menuData
 
-	headers := []string{
-		pad.Right("[r]ank", 13, " "),
-		pad.Right("[n]ame", 13, " "),
-		pad.Right("[s]ymbol", 8, " "),
-		pad.Left("[p]rice", 10, " "),
-		pad.Left("[m]arket cap", 17, " "),
-		pad.Left("24H [v]olume", 15, " "),
-		pad.Left("[1]H%", 9, " "),
-		pad.Left("[2]4H%", 9, " "),
-		pad.Left("[7]D%", 9, " "),
-		pad.Left("[t]otal supply", 20, " "),
-		pad.Left("[a]vailable supply", 19, " "),
-		pad.Left("[l]ast updated", 17, " "),
-	}
-	header := ""
-	for _, h := range headers {
-		header = fmt.Sprintf("%s%s", header, h)
+	var headers []string
+	s.headerSpans = nil
+	x := 0
+	for _, col := range cols {
+		headers = append(headers, padCell(col, col.header, widths[col.id]))
+		s.headerSpans = append(s.headerSpans, headerSpan{start: x, end: x + widths[col.id], column: col})
+		x += widths[col.id] + len(columnGap)
+	}
+	header := strings.Join(headers, columnGap)
+
+	// mark that there are more columns to scroll to
+	if s.scrollCol > 0 || !s.scrollEnd {
+		left, right := " ", " "
+		if s.scrollCol > 0 {
+			left = "<"
+		}
+		if !s.scrollEnd {
+			right = ">"
+		}
+		end := utf8.RuneCountInString(header)
+		if s.scrollCol > 0 {
+			s.headerSpans = append(s.headerSpans, headerSpan{start: end, end: end + 1, scroll: -1})
+		}
+		if !s.scrollEnd {
+			s.headerSpans = append(s.headerSpans, headerSpan{start: end + 1, end: end + 2, scroll: 1})
+		}
+		header = fmt.Sprintf("%s%s%s", header, left, right)
 	}
 
 	s.menuHeader = header
 }
 
-// SetColorPairs sets color pairs
+// SetColorPairs sets color pairs from the theme
 func (s *Service) setColorPairs() {
-	switch s.primaryColor {
-	case "green":
-		gc.InitPair(1, gc.C_GREEN, gc.C_BLACK)
-	case "cyan", "blue":
-		gc.InitPair(1, gc.C_CYAN, gc.C_BLACK)
-	case "magenta", "pink", "purple":
-		gc.InitPair(1, gc.C_MAGENTA, gc.C_BLACK)
-	case "white":
-		gc.InitPair(1, gc.C_WHITE, gc.C_BLACK)
-	case "red":
-		gc.InitPair(1, gc.C_RED, gc.C_BLACK)
-	case "yellow", "orange":
-		gc.InitPair(1, gc.C_YELLOW, gc.C_BLACK)
-	default:
-		gc.InitPair(1, gc.C_WHITE, gc.C_BLACK)
-	}
-
-	gc.InitPair(2, gc.C_BLACK, gc.C_BLACK)
-	gc.InitPair(3, gc.C_BLACK, gc.C_GREEN)
-	gc.InitPair(4, gc.C_BLACK, gc.C_CYAN)
-	gc.InitPair(5, gc.C_WHITE, gc.C_BLUE)
-	gc.InitPair(6, gc.C_BLACK, -1)
+	s.colorPairs = map[int16][2]int16{}
+
+	// ncurses pairs take palette indexes so truecolor themes are approximated
+	depth := s.depth
+	if gc.Colors() < theme.Colors256 {
+		depth = theme.Colors8
+	}
+	color := func(c theme.Color) int16 {
+		return int16(c.Index(depth))
+	}
+
+	// the hidden window boxes and text on the highlight are drawn in the
+	// background color, black when it's the terminal's default
+	bg := color(s.theme.Background)
+	hidden := bg
+	if hidden < 0 {
+		hidden = gc.C_BLACK
+	}
+
+	s.initPair(1, color(s.theme.Accent), bg)
+	s.initPair(2, hidden, hidden)
+	s.initPair(3, gc.C_BLACK, gc.C_GREEN)
+	s.initPair(4, gc.C_BLACK, gc.C_CYAN)
+	s.initPair(5, gc.C_WHITE, gc.C_BLUE)
+	s.initPair(6, hidden, bg)
+	s.initPair(7, hidden, color(s.theme.Highlight))
+	s.initPair(8, color(s.theme.Text), bg)
+}
+
+// highlightAttr returns the attribute for the selected row, reversed when the
+// theme has no highlight color
+func (s *Service) highlightAttr() gc.Char {
+	if s.theme.Highlight.IsDefault() {
+		return gc.A_REVERSE
+	}
+	return gc.ColorPair(7)
+}
+
+// initPair inits a color pair, keeping its colors for screen captures
+func (s *Service) initPair(pair, fg, bg int16) {
+	gc.InitPair(pair, fg, bg)
+	s.colorPairs[pair] = [2]int16{fg, bg}
 }
 
 // RenderMainWindow renders main window
@@ -408,10 +620,12 @@ func (s *Service) resizeWindows() {
 	gc.ResizeTerm(s.screenRows, s.screenCols)
 	//s.log(fmt.Sprintf("%v %v", s.screenCols, s.screenRows))
 	s.renderMainWindow()
+	s.setMenuData()
 	s.renderMenu()
 	s.renderHelpBar()
 	s.renderLogWindow()
 	s.renderHelpWindow()
+	s.renderCalculator()
 }
 
 func (s *Service) renderHelpBar() error {
@@ -430,12 +644,30 @@ func (s *Service) renderHelpBar() error {
 	s.helpbarwin.Box(0, 0)
 	s.helpbarwin.ColorOff(2)
 	s.helpbarwin.ColorOn(1)
-	s.helpbarwin.MovePrint(0, 0, "[q]uit [h]elp")
+	s.helpbarwin.MovePrint(0, 0, s.helpBarText())
 	s.helpbarwin.ColorOff(1)
 	s.helpbarwin.Refresh()
 	return nil
 }
 
+// helpBarText returns the help bar text with the paging status
+func (s *Service) helpBarText() string {
+	text := fmt.Sprintf("[q]uit [h]elp  loaded %d of %d", len(s.coins), s.totalCoins)
+	if s.filter != "" {
+		text = fmt.Sprintf("%s  filter: %s (%d matches)", text, s.filter, len(s.rows))
+	}
+	return text
+}
+
+// renderPrompt renders a prompt in place of the help bar text
+func (s *Service) renderPrompt(prompt string) {
+	s.helpbarwin.Clear()
+	s.helpbarwin.ColorOn(1)
+	s.helpbarwin.MovePrint(0, 0, prompt)
+	s.helpbarwin.ColorOff(1)
+	s.helpbarwin.Refresh()
+}
+
 func (s *Service) renderLogWindow() error {
 	var err error
 	if s.logwin == nil {
@@ -491,9 +723,36 @@ func (s *Service) renderHelpWindow() error {
 		return nil
 	}
 
+	lines := []string{
+		"<up> or <k> to navigate up",
+		"<down> or <j> to navigate down",
+		"<ctrl-u> to to page up",
+		"<ctrl-d> to to page down",
+		"<left> or <right> to scroll columns",
+		"<click> a header to sort by it",
+		"<click> a row to select it",
+		"<wheel> to navigate up or down",
+		"<enter> or <space> to open coin link",
+		"</> to filter by name or symbol",
+		"<A> to load all coins",
+		"<c> to choose columns",
+		"<x> to convert between coins",
+		"<S> to save a screen capture",
+		"<h> or <?> to toggle help",
+	}
+	for _, col := range s.visibleColumns() {
+		lines = append(lines, fmt.Sprintf("<%s> to sort by %s", col.key, col.name))
+	}
+	lines = append(lines, "<q> or <esc> to quit application.")
+
+	height := len(lines) + 2
+	if height > s.screenRows-1 {
+		height = s.screenRows - 1
+	}
+
 	var err error
 	if s.helpwin == nil {
-		s.helpwin, err = gc.NewWindow(21, 40, (s.screenRows/2)-11, (s.screenCols/2)-20)
+		s.helpwin, err = gc.NewWindow(height, 40, (s.screenRows/2)-(height/2), (s.screenCols/2)-20)
 		if err != nil {
 			return err
 		}
@@ -503,33 +762,121 @@ func (s *Service) renderHelpWindow() error {
 	s.helpwin.Clear()
 	s.helpwin.SetBackground(gc.ColorPair(1))
 	s.helpwin.ColorOn(1)
-	s.helpwin.Resize(11, 40)
-	s.helpwin.MoveWindow((s.screenRows/2)-11, (s.screenCols/2)-20)
+	s.helpwin.Resize(height, 40)
+	s.helpwin.MoveWindow((s.screenRows/2)-(height/2), (s.screenCols/2)-20)
 	s.helpwin.Box(0, 0)
 	s.helpwin.MovePrint(0, 1, "Help")
-	s.helpwin.MovePrint(1, 1, "<up> or <k> to navigate up")
-	s.helpwin.MovePrint(2, 1, "<down> or <j> to navigate down")
-	s.helpwin.MovePrint(3, 1, "<ctrl-u> to to page up")
-	s.helpwin.MovePrint(4, 1, "<ctrl-d> to to page down")
-	s.helpwin.MovePrint(5, 1, "<enter> or <space> to open coin link")
-	s.helpwin.MovePrint(6, 1, "<1> to sort by 1 hour change")
-	s.helpwin.MovePrint(7, 1, "<2> to sort by 24 hour volume")
-	s.helpwin.MovePrint(8, 1, "<7> to sort by 7 day change")
-	s.helpwin.MovePrint(9, 1, "<a> to sort by available supply")
-	s.helpwin.MovePrint(10, 1, "<h> or <?> to toggle help")
-	s.helpwin.MovePrint(11, 1, "<l> to sort by last updated")
-	s.helpwin.MovePrint(12, 1, "<m> to sort by market cap")
-	s.helpwin.MovePrint(13, 1, "<n> to sort by name")
-	s.helpwin.MovePrint(14, 1, "<r> to sort by rank")
-	s.helpwin.MovePrint(15, 1, "<s> to sort by symbol")
-	s.helpwin.MovePrint(16, 1, "<t> to sort by total supply")
-	s.helpwin.MovePrint(17, 1, "<p> to sort by price")
-	s.helpwin.MovePrint(18, 1, "<v> to sort by 24 hour volume")
-	s.helpwin.MovePrint(19, 1, "<q> or <esc> to quit application.")
+	for i, line := range lines {
+		if i+1 >= height-1 {
+			break
+		}
+		s.helpwin.MovePrint(i+1, 1, line)
+	}
 	s.helpwin.Refresh()
 	return nil
 }
 
+func (s *Service) togglePicker() {
+	s.pickerVisible = !s.pickerVisible
+	s.pickerItem = 0
+	s.renderColumnPicker()
+}
+
+// handlePickerKey handles key presses while the column picker is open
+func (s *Service) handlePickerKey(ch gc.Key) {
+	chstr := fmt.Sprint(ch)
+	switch {
+	case ch == gc.KEY_DOWN, chstr == "106": // "j"
+		if s.pickerItem < len(s.columnOrder)-1 {
+			s.pickerItem++
+		}
+	case ch == gc.KEY_UP, chstr == "107": // "k"
+		if s.pickerItem > 0 {
+			s.pickerItem--
+		}
+	case ch == gc.KEY_RETURN, ch == gc.KEY_ENTER, chstr == "32":
+		col := s.columnOrder[s.pickerItem]
+		s.columnVisible[col.id] = !s.columnVisible[col.id]
+	case chstr == "74", chstr == "43": // "J", "+"
+		if s.pickerItem < len(s.columnOrder)-1 {
+			i := s.pickerItem
+			s.columnOrder[i], s.columnOrder[i+1] = s.columnOrder[i+1], s.columnOrder[i]
+			s.pickerItem++
+		}
+	case chstr == "75", chstr == "45": // "K", "-"
+		if s.pickerItem > 0 {
+			i := s.pickerItem
+			s.columnOrder[i], s.columnOrder[i-1] = s.columnOrder[i-1], s.columnOrder[i]
+			s.pickerItem--
+		}
+	case chstr == "99", chstr == "113", chstr == "27": // "c", "q", esc
+		s.togglePicker()
+		return
+	}
+
+	s.renderColumnPicker()
+}
+
+// renderColumnPicker renders the column picker and re-renders the table with
+// the chosen columns
+func (s *Service) renderColumnPicker() error {
+	s.setMenuData()
+	s.renderMenu()
+
+	if !s.pickerVisible {
+		if s.pickerwin != nil {
+			s.pickerwin.ClearOk(true)
+			s.pickerwin.Clear()
+			s.pickerwin.SetBackground(gc.ColorPair(6))
+			s.pickerwin.ColorOn(6)
+			s.pickerwin.Resize(0, 0)
+			s.pickerwin.MoveWindow(200, 200)
+			s.pickerwin.Refresh()
+			s.renderMenu()
+		}
+		return nil
+	}
+
+	height := len(s.columnOrder) + 4
+	if height > s.screenRows-1 {
+		height = s.screenRows - 1
+	}
+
+	var err error
+	if s.pickerwin == nil {
+		s.pickerwin, err = gc.NewWindow(height, 40, (s.screenRows/2)-(height/2), (s.screenCols/2)-20)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.pickerwin.Clear()
+	s.pickerwin.SetBackground(gc.ColorPair(1))
+	s.pickerwin.ColorOn(1)
+	s.pickerwin.Resize(height, 40)
+	s.pickerwin.MoveWindow((s.screenRows/2)-(height/2), (s.screenCols/2)-20)
+	s.pickerwin.Box(0, 0)
+	s.pickerwin.MovePrint(0, 1, "Columns")
+	for i, col := range s.columnOrder {
+		if i+1 >= height-3 {
+			break
+		}
+		mark := "[ ]"
+		if s.columnVisible[col.id] {
+			mark = "[x]"
+		}
+		if i == s.pickerItem {
+			s.pickerwin.AttrOn(gc.A_REVERSE)
+		}
+		s.pickerwin.MovePrint(i+1, 1, fmt.Sprintf("%s %s", mark, col.name))
+		s.pickerwin.AttrOff(gc.A_REVERSE)
+	}
+	s.pickerwin.MovePrint(height-3, 1, "<space> toggle, <J>/<K> move")
+	s.pickerwin.MovePrint(height-2, 1, "<c> or <esc> to close")
+	s.pickerwin.Refresh()
+	return nil
+}
+
 // OnWindowResize sends event to channel when resize event occurs
 func (s *Service) onWindowResize(channel chan os.Signal) {
 	//stdScr, _ := gc.Init()
@@ -579,6 +926,10 @@ func (s *Service) renderMenu() error {
 	} else {
 		s.menu.UnPost()
 		s.menu.SetItems(s.menuItems)
+		s.menuTop = 0
+		if s.currentItem >= len(s.menuItems) {
+			s.currentItem = len(s.menuItems) - 1
+		}
 		s.menu.Current(s.menuItems[s.currentItem])
 	}
 
@@ -597,6 +948,8 @@ func (s *Service) renderMenu() error {
 		s.menu.Option(gc.O_ONEVALUE, false)
 		s.menu.Format(s.menuHeight, 0)
 		s.menu.Mark("")
+		s.menu.SetForeground(s.highlightAttr())
+		s.menu.SetBackground(gc.ColorPair(8))
 	} else {
 		s.menusubwin.Resize(s.menuHeight, s.menuWidth)
 		s.menuwin.Resize(s.menuHeight, s.menuWidth)
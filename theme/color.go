@@ -0,0 +1,194 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is a theme color: default, one of the 8 basic color names, a 256
+// color palette index or a #rrggbb hex color
+type Color string
+
+// Default is the terminal's default color
+const Default Color = "default"
+
+// Color depths
+const (
+	Colors8   = 8
+	Colors256 = 256
+	TrueColor = 1 << 24
+)
+
+// basicNames are the 8 basic colors in palette order
+var basicNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// palette16 is the RGB of the 16 basic and bright colors
+var palette16 = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// cubeLevels are the channel levels of the 6x6x6 color cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// IsDefault returns true for the terminal's default color
+func (c Color) IsDefault() bool {
+	return c == "" || strings.ToLower(string(c)) == string(Default)
+}
+
+// Valid returns true if the color parses
+func (c Color) Valid() bool {
+	if c.IsDefault() {
+		return true
+	}
+	_, ok := c.index()
+	if ok {
+		return true
+	}
+	_, _, _, ok = c.hex()
+	return ok
+}
+
+// Index returns the palette index of the color at the color depth, the
+// nearest basic color for 8 colors and the nearest palette color for hex
+// colors, or -1 for the default color
+func (c Color) Index(depth int) int {
+	if c.IsDefault() {
+		return -1
+	}
+
+	i, ok := c.index()
+	if !ok {
+		r, g, b, ok := c.hex()
+		if !ok {
+			return -1
+		}
+		i = nearest(r, g, b, 256)
+	}
+
+	if depth < Colors256 && i >= 8 {
+		r, g, b := PaletteRGB(i)
+		return nearest(r, g, b, 8)
+	}
+	return i
+}
+
+// RGB returns the red, green and blue of the color, false for the default
+// color
+func (c Color) RGB() (uint8, uint8, uint8, bool) {
+	if c.IsDefault() {
+		return 0, 0, 0, false
+	}
+	if r, g, b, ok := c.hex(); ok {
+		return r, g, b, true
+	}
+	if i, ok := c.index(); ok {
+		r, g, b := PaletteRGB(i)
+		return r, g, b, true
+	}
+	return 0, 0, 0, false
+}
+
+// Hex returns the #rrggbb hex of the color, or fallback for the default color
+func (c Color) Hex(fallback string) string {
+	r, g, b, ok := c.RGB()
+	if !ok {
+		return fallback
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// BasicName returns the name of the nearest basic color, or default
+func (c Color) BasicName() string {
+	i := c.Index(8)
+	if i < 0 {
+		return string(Default)
+	}
+	return basicNames[i]
+}
+
+// ANSI returns the SGR parameters for the color at the color depth, with base
+// 30 for foreground and 40 for background colors. ie. 32 | 38;5;28 |
+// 38;2;0;135;0
+func (c Color) ANSI(base, depth int) string {
+	if c.IsDefault() {
+		return strconv.Itoa(base + 9)
+	}
+	if depth >= TrueColor {
+		if r, g, b, ok := c.hex(); ok {
+			return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+		}
+	}
+	i := c.Index(depth)
+	if i < 8 {
+		return strconv.Itoa(base + i)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, i)
+}
+
+// index parses a basic color name or palette index
+func (c Color) index() (int, bool) {
+	s := strings.ToLower(string(c))
+	for i, name := range basicNames {
+		if s == name {
+			return i, true
+		}
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 || i > 255 {
+		return 0, false
+	}
+	return i, true
+}
+
+// hex parses a #rrggbb color
+func (c Color) hex() (uint8, uint8, uint8, bool) {
+	s := string(c)
+	if len(s) != 7 || s[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+// PaletteRGB returns the RGB of a 256 color palette index
+func PaletteRGB(i int) (uint8, uint8, uint8) {
+	switch {
+	case i < 0:
+		return palette16[7][0], palette16[7][1], palette16[7][2]
+	case i < 16:
+		return palette16[i][0], palette16[i][1], palette16[i][2]
+	case i < 232:
+		i -= 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	case i < 256:
+		gray := uint8(8 + (i-232)*10)
+		return gray, gray, gray
+	}
+	return palette16[7][0], palette16[7][1], palette16[7][2]
+}
+
+// nearest returns the palette index below n closest to the RGB
+func nearest(r, g, b uint8, n int) int {
+	best, bestDist := 0, -1
+	for i := 0; i < n; i++ {
+		// skip the bright colors for 256 colors, their RGB varies the most
+		// between terminals
+		if n > 16 && i >= 8 && i < 16 {
+			continue
+		}
+		pr, pg, pb := PaletteRGB(i)
+		dr, dg, db := int(r)-int(pr), int(g)-int(pg), int(b)-int(pb)
+		dist := dr*dr + dg*dg + db*db
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
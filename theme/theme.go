@@ -0,0 +1,189 @@
+// Package theme defines the color themes shared by the dashboards, table,
+// ticker and exports
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Theme is a set of colors for each role
+type Theme struct {
+	Accent     Color `json:"accent"`     // labels, chart lines and the primary color
+	Text       Color `json:"text"`       // values and table rows
+	Border     Color `json:"border"`     // widget borders
+	Up         Color `json:"up"`         // gains
+	Down       Color `json:"down"`       // losses
+	Highlight  Color `json:"highlight"`  // selected table row background
+	Background Color `json:"background"` // screen background
+}
+
+// Presets are the built in themes
+var Presets = map[string]Theme{
+	"dark": {
+		Accent:     "green",
+		Text:       "white",
+		Border:     "green",
+		Up:         "green",
+		Down:       "red",
+		Highlight:  "white",
+		Background: "default",
+	},
+	"light": {
+		Accent:     "#005faf",
+		Text:       "#262626",
+		Border:     "#8a8a8a",
+		Up:         "#008700",
+		Down:       "#d70000",
+		Highlight:  "#005faf",
+		Background: "#ffffff",
+	},
+	"solarized": {
+		Accent:     "#268bd2",
+		Text:       "#839496",
+		Border:     "#586e75",
+		Up:         "#859900",
+		Down:       "#dc322f",
+		Highlight:  "#268bd2",
+		Background: "#002b36",
+	},
+	// blue and orange gains and losses stay distinct with red-green color
+	// blindness
+	"colorblind": {
+		Accent:     "#56b4e9",
+		Text:       "white",
+		Border:     "#56b4e9",
+		Up:         "#0072b2",
+		Down:       "#e69f00",
+		Highlight:  "#56b4e9",
+		Background: "default",
+	},
+}
+
+// NoColor is the theme used when the NO_COLOR environment variable is set
+var NoColor = Theme{
+	Accent:     Default,
+	Text:       Default,
+	Border:     Default,
+	Up:         Default,
+	Down:       Default,
+	Highlight:  Default,
+	Background: Default,
+}
+
+// Names returns the preset and custom theme names
+func Names(custom map[string]Theme) []string {
+	var names []string
+	for name := range Presets {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, ok := Presets[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the named theme, custom themes take precedence over presets
+// and fall back to the dark preset for roles they leave out
+func Get(name string, custom map[string]Theme) (Theme, error) {
+	if name == "" {
+		name = "dark"
+	}
+
+	if t, ok := custom[name]; ok {
+		t = t.withDefaults(Presets["dark"])
+		return t, t.Validate()
+	}
+	if t, ok := Presets[name]; ok {
+		return t, nil
+	}
+
+	return Theme{}, fmt.Errorf("unknown theme %q, available themes: %s", name, strings.Join(Names(custom), " | "))
+}
+
+// FromEnv returns the theme, or the no color theme when the NO_COLOR
+// environment variable is set
+func (t Theme) FromEnv() Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	return t
+}
+
+// WithAccent returns the theme with the accent and border colors set from a
+// -color flag value. ie. green | cyan | magenta | red | yellow | white
+func (t Theme) WithAccent(name string) Theme {
+	c := accentAliases[name]
+	if c == "" {
+		c = Color(name)
+	}
+	t.Accent = c
+	t.Border = c
+	return t
+}
+
+// accentAliases are the color names accepted by -color before themes
+var accentAliases = map[string]Color{
+	"blue":   "cyan",
+	"pink":   "magenta",
+	"purple": "magenta",
+	"orange": "yellow",
+}
+
+// Validate returns an error for invalid colors
+func (t Theme) Validate() error {
+	roles := []struct {
+		name string
+		c    Color
+	}{
+		{"accent", t.Accent},
+		{"text", t.Text},
+		{"border", t.Border},
+		{"up", t.Up},
+		{"down", t.Down},
+		{"highlight", t.Highlight},
+		{"background", t.Background},
+	}
+	for _, role := range roles {
+		if !role.c.Valid() {
+			return fmt.Errorf("invalid %s color %q, expected a color name, 256 color index or #rrggbb", role.name, role.c)
+		}
+	}
+	return nil
+}
+
+// withDefaults fills the unset roles from d
+func (t Theme) withDefaults(d Theme) Theme {
+	for _, pair := range []struct{ c, d *Color }{
+		{&t.Accent, &d.Accent},
+		{&t.Text, &d.Text},
+		{&t.Border, &d.Border},
+		{&t.Up, &d.Up},
+		{&t.Down, &d.Down},
+		{&t.Highlight, &d.Highlight},
+		{&t.Background, &d.Background},
+	} {
+		if *pair.c == "" {
+			*pair.c = *pair.d
+		}
+	}
+	return t
+}
+
+// Depth returns the number of colors the terminal supports from the
+// environment, TrueColor, Colors256 or Colors8
+func Depth() int {
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return TrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Colors256
+	}
+	return Colors8
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/miguelmota/cryptocharts/theme"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// defaultTickerFormat is the default ticker template for each coin
+const defaultTickerFormat = `{{.Symbol}} {{usd .PriceUsd}} {{change .PercentChange24h}}`
+
+// Ticker prints a one line summary of coins, rendered for each coin by a
+// text/template over cmc.Coin
+type Ticker struct {
+	coinIDs []string
+	tmpl    *template.Template
+	color   bool
+	theme   theme.Theme
+	depth   int
+	title   bool
+	refresh time.Duration
+	out     io.Writer
+}
+
+// NewTicker returns a new ticker for the coin ids. Color adds ANSI colors to
+// changes from the theme's up and down colors at the color depth and title
+// sets the terminal window title instead of printing lines
+func NewTicker(coinIDs []string, format string, th theme.Theme, depth int, color, title bool, refresh time.Duration, out io.Writer) (*Ticker, error) {
+	if len(coinIDs) == 0 {
+		return nil, fmt.Errorf("no coins given to ticker")
+	}
+	if format == "" {
+		format = defaultTickerFormat
+	}
+
+	t := &Ticker{
+		coinIDs: coinIDs,
+		color:   color && !title,
+		theme:   th,
+		depth:   depth,
+		title:   title,
+		refresh: refresh,
+		out:     out,
+	}
+
+	tmpl, err := template.New("ticker").Funcs(t.funcs()).Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	t.tmpl = tmpl
+
+	return t, nil
+}
+
+// funcs returns the template functions
+func (t *Ticker) funcs() template.FuncMap {
+	return template.FuncMap{
+		"usd":     tickerUSD,
+		"comma":   humanize.Commaf,
+		"percent": func(value float64) string { return fmt.Sprintf("%.1f%%", value) },
+		"change":  t.change,
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+	}
+}
+
+// change formats a % change with an arrow, colored unless the ticker is plain
+func (t *Ticker) change(value float64) string {
+	arrow, c := "▲", t.theme.Up
+	if value < 0 {
+		arrow, c = "▼", t.theme.Down
+	}
+	text := fmt.Sprintf("%s%.1f%%", arrow, math.Abs(value))
+	if t.color && !c.IsDefault() {
+		return fmt.Sprintf("\x1b[%sm%s\x1b[0m", c.ANSI(30, t.depth), text)
+	}
+	return text
+}
+
+// tickerUSD formats a price compactly, dropping cents from large prices.
+// ie. $64,210 | $3.41 | $0.00001235
+func tickerUSD(value float64) string {
+	switch {
+	case math.Abs(value) >= 1000:
+		return "$" + humanize.Comma(int64(math.Round(value)))
+	case math.Abs(value) >= 1:
+		return fmt.Sprintf("$%.2f", value)
+	default:
+		return "$" + strconv.FormatFloat(roundSignificant(value, 4), 'f', -1, 64)
+	}
+}
+
+// roundSignificant rounds to n significant digits
+func roundSignificant(value float64, n int) float64 {
+	if value == 0 {
+		return 0
+	}
+	scale := math.Pow(10, float64(n)-math.Ceil(math.Log10(math.Abs(value))))
+	return math.Round(value*scale) / scale
+}
+
+// Line fetches the coins and renders the ticker line
+func (t *Ticker) Line() (string, error) {
+	var parts []string
+	for _, id := range t.coinIDs {
+		coin, err := cmc.GetCoinData(id)
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", id, err)
+		}
+
+		var buf bytes.Buffer
+		err = t.tmpl.Execute(&buf, coin)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, buf.String())
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// Print prints the ticker line, or sets it as the terminal window title
+func (t *Ticker) Print() error {
+	line, err := t.Line()
+	if err != nil {
+		return err
+	}
+
+	if t.title {
+		// OSC 2 sets the window title, tmux picks it up as the pane title
+		_, err = fmt.Fprintf(t.out, "\x1b]2;%s\x07", line)
+		return err
+	}
+
+	_, err = fmt.Fprintln(t.out, line)
+	return err
+}
+
+// Run prints the ticker once, or every refresh interval until the process
+// exits
+func (t *Ticker) Run(once bool) error {
+	if once {
+		return t.Print()
+	}
+
+	ticker := time.NewTicker(t.refresh)
+	for {
+		err := t.Print()
+		if err != nil {
+			log.Println(err)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets holds the web dashboard page, script and styles
+//
+//go:embed web
+var webAssets embed.FS
+
+// WebHandler returns the HTTP handler serving the web dashboard alongside
+// the JSON API it's fed by
+func (s *Server) WebHandler() http.Handler {
+	assets, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err)
+	}
+
+	api := s.Handler()
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	for _, path := range []string{"/coins", "/coins/", "/global", "/global/", "/events"} {
+		mux.Handle(path, api)
+	}
+	return mux
+}
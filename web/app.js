@@ -0,0 +1,344 @@
+(function () {
+  'use strict';
+
+  var state = {
+    coins: [],
+    global: null,
+    updated: 0,
+    view: 'chart',
+    coin: 'bitcoin',
+    range: '7d',
+    window: '24h',
+    sort: 'rank',
+    desc: false,
+    filter: ''
+  };
+
+  var columns = [
+    {id: 'rank', header: '#', value: function (c) { return num(c.rank); }, format: String},
+    {id: 'name', header: 'Name', left: true, value: function (c) { return c.name.toLowerCase(); }, format: function (v, c) { return c.name; }},
+    {id: 'symbol', header: 'Symbol', left: true, value: function (c) { return c.symbol; }, format: String},
+    {id: 'price', header: 'Price (USD)', value: function (c) { return num(c.price_usd); }, format: usd},
+    {id: 'marketcap', header: 'Market Cap (USD)', value: function (c) { return num(c.market_cap_usd); }, format: usd},
+    {id: '24hvolume', header: '24H Volume (USD)', value: function (c) { return num(c['24h_volume_usd']); }, format: usd},
+    {id: '1hchange', header: '%1H', value: function (c) { return num(c.percent_change_1h); }, format: percent, change: true},
+    {id: '24hchange', header: '%24H', value: function (c) { return num(c.percent_change_24h); }, format: percent, change: true},
+    {id: '7dchange', header: '%7D', value: function (c) { return num(c.percent_change_7d); }, format: percent, change: true},
+    {id: 'availablesupply', header: 'Available Supply', value: function (c) { return num(c.available_supply); }, format: amount},
+    {id: 'lastupdated', header: 'Last Updated', value: function (c) { return num(c.last_updated); }, format: time}
+  ];
+
+  function $(id) {
+    return document.getElementById(id);
+  }
+
+  function num(value) {
+    return parseFloat(value) || 0;
+  }
+
+  function usd(value) {
+    var digits = Math.abs(value) < 1 ? 6 : 2;
+    return '$' + value.toLocaleString('en-US', {maximumFractionDigits: digits});
+  }
+
+  function amount(value) {
+    return value.toLocaleString('en-US', {maximumFractionDigits: 0});
+  }
+
+  function percent(value) {
+    return value.toFixed(2) + '%';
+  }
+
+  function time(unix) {
+    return new Date(unix * 1000).toLocaleString();
+  }
+
+  function changeClass(value) {
+    return value < 0 ? 'down' : 'up';
+  }
+
+  function getJSON(url) {
+    return fetch(url).then(function (resp) {
+      return resp.json().then(function (body) {
+        if (!resp.ok) {
+          throw new Error(body.error || resp.statusText);
+        }
+        return body;
+      });
+    });
+  }
+
+  function card(label, value, className) {
+    var el = document.createElement('div');
+    el.className = 'card' + (className ? ' ' + className : '');
+    var l = document.createElement('div');
+    l.className = 'label';
+    l.textContent = label;
+    var v = document.createElement('div');
+    v.textContent = value;
+    el.appendChild(l);
+    el.appendChild(v);
+    return el;
+  }
+
+  function changeCard(label, value) {
+    return card(label, percent(value), changeClass(value));
+  }
+
+  function renderCards(container, cards) {
+    container.innerHTML = '';
+    cards.forEach(function (c) {
+      container.appendChild(c);
+    });
+  }
+
+  // renderLineChart draws a [timestamp, value] series into an svg element
+  function renderLineChart(svg, series) {
+    var width = svg.clientWidth || 800;
+    var height = svg.clientHeight || 260;
+    var pad = {top: 8, right: 8, bottom: 20, left: 90};
+    svg.setAttribute('viewBox', '0 0 ' + width + ' ' + height);
+    svg.innerHTML = '';
+
+    if (!series || series.length < 2) {
+      return;
+    }
+
+    var min = Infinity, max = -Infinity;
+    series.forEach(function (p) {
+      min = Math.min(min, p[1]);
+      max = Math.max(max, p[1]);
+    });
+    if (min === max) {
+      max = min + 1;
+    }
+    var t0 = series[0][0], t1 = series[series.length - 1][0];
+
+    function x(t) {
+      return pad.left + (t - t0) / (t1 - t0 || 1) * (width - pad.left - pad.right);
+    }
+
+    function y(v) {
+      return pad.top + (max - v) / (max - min) * (height - pad.top - pad.bottom);
+    }
+
+    var ns = 'http://www.w3.org/2000/svg';
+    function add(tag, attrs, text) {
+      var el = document.createElementNS(ns, tag);
+      Object.keys(attrs).forEach(function (k) {
+        el.setAttribute(k, attrs[k]);
+      });
+      if (text !== undefined) {
+        el.textContent = text;
+      }
+      svg.appendChild(el);
+    }
+
+    for (var i = 0; i <= 4; i++) {
+      var v = min + (max - min) * i / 4;
+      add('line', {x1: pad.left, x2: width - pad.right, y1: y(v), y2: y(v)});
+      add('text', {x: pad.left - 6, y: y(v) + 4, 'text-anchor': 'end'}, v.toLocaleString('en-US', {maximumFractionDigits: 2}));
+    }
+    add('text', {x: pad.left, y: height - 4}, new Date(t0).toLocaleDateString());
+    add('text', {x: width - pad.right, y: height - 4, 'text-anchor': 'end'}, new Date(t1).toLocaleDateString());
+    add('polyline', {points: series.map(function (p) { return x(p[0]) + ',' + y(p[1]); }).join(' ')});
+  }
+
+  function findCoin(id) {
+    for (var i = 0; i < state.coins.length; i++) {
+      if (state.coins[i].id === id) {
+        return Promise.resolve(state.coins[i]);
+      }
+    }
+    return getJSON('/coins/' + encodeURIComponent(id));
+  }
+
+  function renderChart() {
+    var select = $('coin');
+    if (select.options.length !== state.coins.length) {
+      select.innerHTML = '';
+      state.coins.forEach(function (c) {
+        var opt = document.createElement('option');
+        opt.value = c.id;
+        opt.textContent = c.name + ' (' + c.symbol + ')';
+        select.appendChild(opt);
+      });
+    }
+    select.value = state.coin;
+
+    findCoin(state.coin).then(function (c) {
+      renderCards($('chart-cards'), [
+        card('Name', c.name),
+        card('Symbol', c.symbol),
+        card('Price (USD)', usd(num(c.price_usd))),
+        changeCard('% Change (1H)', num(c.percent_change_1h)),
+        changeCard('% Change (24H)', num(c.percent_change_24h)),
+        changeCard('% Change (7D)', num(c.percent_change_7d)),
+        card('Rank', String(c.rank)),
+        card('Market Cap', usd(num(c.market_cap_usd))),
+        card('Volume (24H)', usd(num(c['24h_volume_usd']))),
+        card('Circulating Supply', amount(num(c.available_supply)) + ' ' + c.symbol),
+        card('Total Supply', amount(num(c.total_supply)) + ' ' + c.symbol),
+        card('Last Updated', time(num(c.last_updated)))
+      ]);
+      $('chart-label').textContent = c.symbol + ' Price History: ' + state.range.toUpperCase();
+    }).catch(showError);
+
+    getJSON('/coins/' + encodeURIComponent(state.coin) + '/history?range=' + state.range).then(function (graph) {
+      renderLineChart($('price-chart'), graph.price_usd);
+    }).catch(showError);
+  }
+
+  function renderMovers(list, coins) {
+    list.innerHTML = '';
+    coins.forEach(function (c) {
+      var change = num(c['percent_change_' + state.window]);
+      var li = document.createElement('li');
+      li.innerHTML = '<span></span> <span class="' + changeClass(change) + '"></span>';
+      li.firstChild.textContent = c.symbol;
+      li.lastChild.textContent = (change > 0 ? '+' : '') + percent(change);
+      list.appendChild(li);
+    });
+  }
+
+  function renderGlobal() {
+    var g = state.global;
+    if (g) {
+      renderCards($('global-cards'), [
+        card('Total Market Cap (USD)', usd(g.total_market_cap_usd)),
+        card('Total Volume (24H)', usd(g.total_24h_volume_usd)),
+        card('% Bitcoin Dominance', percent(g.bitcoin_percentage_of_market_cap)),
+        card('Active Currencies', amount(g.active_currencies)),
+        card('Active Assets', amount(g.active_assets)),
+        card('Active Markets', amount(g.active_markets))
+      ]);
+    }
+
+    var key = 'percent_change_' + state.window;
+    var sorted = state.coins.slice().sort(function (a, b) {
+      return num(b[key]) - num(a[key]);
+    });
+    renderMovers($('gainers'), sorted.filter(function (c) { return num(c[key]) > 0; }).slice(0, 5));
+    renderMovers($('losers'), sorted.reverse().filter(function (c) { return num(c[key]) < 0; }).slice(0, 5));
+
+    var label = state.range.toUpperCase();
+    $('marketcap-label').textContent = 'Total Market Cap History: ' + label;
+    $('volume-label').textContent = 'Total Volume (24H) History: ' + label;
+    $('dominance-label').textContent = '% Bitcoin Dominance History: ' + label;
+
+    getJSON('/global/history?range=' + state.range).then(function (history) {
+      renderLineChart($('marketcap-chart'), history.market_cap_usd);
+      renderLineChart($('volume-chart'), history.volume_usd);
+      renderLineChart($('dominance-chart'), history.bitcoin_dominance);
+    }).catch(showError);
+  }
+
+  function renderTable() {
+    var header = $('table-header');
+    header.innerHTML = '';
+    columns.forEach(function (col) {
+      var th = document.createElement('th');
+      th.className = col.left ? 'left' : '';
+      th.textContent = col.header + (state.sort === col.id ? (state.desc ? ' ▼' : ' ▲') : '');
+      th.addEventListener('click', function () {
+        state.desc = state.sort === col.id ? !state.desc : false;
+        state.sort = col.id;
+        renderTable();
+      });
+      header.appendChild(th);
+    });
+
+    var sortCol = columns.filter(function (col) { return col.id === state.sort; })[0];
+    var filter = state.filter.toLowerCase();
+    var coins = state.coins.filter(function (c) {
+      return !filter || c.name.toLowerCase().indexOf(filter) !== -1 || c.symbol.toLowerCase().indexOf(filter) !== -1;
+    }).sort(function (a, b) {
+      var va = sortCol.value(a), vb = sortCol.value(b);
+      var cmp = va < vb ? -1 : va > vb ? 1 : 0;
+      return state.desc ? -cmp : cmp;
+    });
+
+    var body = $('table-body');
+    body.innerHTML = '';
+    coins.forEach(function (c) {
+      var tr = document.createElement('tr');
+      columns.forEach(function (col) {
+        var td = document.createElement('td');
+        var value = col.value(c);
+        td.className = (col.left ? 'left ' : '') + (col.change ? changeClass(value) : '');
+        td.textContent = col.format(value, c);
+        tr.appendChild(td);
+      });
+      tr.addEventListener('click', function () {
+        location.hash = 'chart/' + c.id;
+      });
+      body.appendChild(tr);
+    });
+  }
+
+  function render() {
+    Array.prototype.forEach.call(document.querySelectorAll('.view'), function (el) {
+      el.classList.toggle('active', el.id === state.view);
+    });
+    Array.prototype.forEach.call(document.querySelectorAll('nav a'), function (el) {
+      el.classList.toggle('active', el.getAttribute('data-view') === state.view);
+    });
+    Array.prototype.forEach.call(document.querySelectorAll('.range'), function (el) {
+      el.value = state.range;
+    });
+
+    if (state.view === 'global') {
+      renderGlobal();
+    } else if (state.view === 'table') {
+      renderTable();
+    } else {
+      renderChart();
+    }
+  }
+
+  function showError(err) {
+    $('status').textContent = 'error: ' + err.message;
+  }
+
+  // route reads the view and coin from the url hash. ie. #chart/ethereum
+  function route() {
+    var parts = location.hash.replace(/^#/, '').split('/');
+    state.view = ['chart', 'global', 'table'].indexOf(parts[0]) !== -1 ? parts[0] : 'chart';
+    if (state.view === 'chart' && parts[1]) {
+      state.coin = decodeURIComponent(parts[1]);
+    }
+    render();
+  }
+
+  $('coin').addEventListener('change', function (e) {
+    location.hash = 'chart/' + e.target.value;
+  });
+  Array.prototype.forEach.call(document.querySelectorAll('.range'), function (el) {
+    el.addEventListener('change', function (e) {
+      state.range = e.target.value;
+      render();
+    });
+  });
+  $('window').addEventListener('change', function (e) {
+    state.window = e.target.value;
+    render();
+  });
+  $('filter').addEventListener('input', function (e) {
+    state.filter = e.target.value;
+    renderTable();
+  });
+  window.addEventListener('hashchange', route);
+
+  var events = new EventSource('/events');
+  events.addEventListener('refresh', function (e) {
+    var data = JSON.parse(e.data);
+    state.coins = data.coins || [];
+    state.global = data.global;
+    state.updated = data.updated;
+    $('status').textContent = 'updated ' + time(state.updated);
+    route();
+  });
+  events.onerror = function () {
+    $('status').textContent = 'reconnecting...';
+  };
+})();
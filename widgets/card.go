@@ -0,0 +1,36 @@
+package widgets
+
+import (
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/format"
+)
+
+// NewStatCard returns a stat card showing the value under the label
+func NewStatCard(label string, value string, primaryColor ui.Attribute) *ui.Par {
+	par := ui.NewPar(value)
+	par.Height = 3
+	par.Width = 20
+	par.Y = 1
+	par.TextFgColor = TextColor()
+	par.BorderLabel = label
+	par.BorderLabelFg = primaryColor
+	par.BorderFg = BorderColor()
+	return par
+}
+
+// NewChangeCard returns a stat card showing a % change colored with the
+// theme's up and down colors
+func NewChangeCard(label string, change float64) *ui.Par {
+	par := NewStatCard(label, "", ChangeColor(change))
+	SetChange(par, change)
+	return par
+}
+
+// SetChange sets the % change shown by a change card
+func SetChange(par *ui.Par, change float64) {
+	changeColor := ChangeColor(change)
+	par.Text = format.Percent(change)
+	par.TextFgColor = changeColor
+	par.BorderFg = changeColor
+	par.BorderLabelFg = changeColor
+}
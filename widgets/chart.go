@@ -0,0 +1,257 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"sort"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/daterange"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// SeriesChart is a braille line chart of a [timestamp, value] series. The
+// series is resampled to the chart's width on render, and the missing
+// intervals of the series are left blank
+type SeriesChart struct {
+	ui.LineChart
+	Series  [][]float64
+	Samples []data.Sample // the plotted samples, two per cell
+	Cursor  int           // index of the series point under the cursor, -1 for none
+	fitted  int           // inner width the samples were fitted to
+}
+
+// NewSeriesChart returns a line chart of the series
+func NewSeriesChart(label string, series [][]float64, height int, primaryColor ui.Attribute) *SeriesChart {
+	lc := &SeriesChart{LineChart: *ui.NewLineChart(), Series: series, Cursor: -1}
+	lc.Width = 100
+	lc.Height = height
+	lc.AxesColor = primaryColor
+	lc.LineColor = primaryColor | ui.AttrBold
+	lc.BorderFg = BorderColor()
+	lc.BorderLabel = label
+	lc.BorderLabelFg = primaryColor
+	return lc
+}
+
+// NewPriceChart returns a line chart of the coin's USD price history over
+// the date range
+func NewPriceChart(symbol string, graph cmc.CoinGraph, dateRange daterange.Range, height int, primaryColor ui.Attribute) *SeriesChart {
+	return NewSeriesChart(PriceChartLabel(symbol, dateRange), graph.PriceUsd, height, primaryColor)
+}
+
+// PriceChartLabel returns the label of a price history chart over the date
+// range
+func PriceChartLabel(symbol string, dateRange daterange.Range) string {
+	return fmt.Sprintf("%s %s: %s", symbol, "Price History", dateRange.Label())
+}
+
+// SetSeries replaces the chart's series and hides the cursor. termui only
+// ever widens a chart's value bounds, so they're reset by starting over
+// from a new chart in the same place
+func (c *SeriesChart) SetSeries(series [][]float64) {
+	lc := ui.NewLineChart()
+	lc.Block = c.Block
+	lc.AxesColor = c.AxesColor
+	lc.LineColor = c.LineColor
+	c.LineChart = *lc
+	c.Series = series
+	c.Samples = nil
+	c.Cursor = -1
+	c.fitted = 0
+}
+
+// Buffer implements the termui Bufferer interface
+func (c *SeriesChart) Buffer() ui.Buffer {
+	c.fit()
+	buf := c.LineChart.Buffer()
+
+	originX, ok := c.originX(buf)
+	if !ok {
+		return buf
+	}
+	area := c.InnerBounds()
+	for i := 0; 2*i+1 < len(c.Samples); i++ {
+		if !c.Samples[2*i].Gap || !c.Samples[2*i+1].Gap {
+			continue
+		}
+		for y := area.Min.Y; y < area.Max.Y-2; y++ {
+			buf.Set(originX+1+i, y, ui.Cell{Ch: ' ', Bg: c.Bg})
+		}
+	}
+
+	// the cursor is a line through the empty cells of its column
+	if col := c.column(c.Cursor); col >= 0 {
+		for y := area.Min.Y; y < area.Max.Y-2; y++ {
+			if ch := buf.At(originX+1+col, y).Ch; ch == ' ' || ch == 0 {
+				buf.Set(originX+1+col, y, ui.Cell{Ch: ui.VERTICAL_LINE, Fg: c.AxesColor, Bg: c.Bg})
+			}
+		}
+	}
+	return buf
+}
+
+// fit resamples the series to two points per cell of the plot area when the
+// chart's width changed
+func (c *SeriesChart) fit() {
+	width := c.InnerBounds().Dx()
+	if width == c.fitted || len(c.Series) == 0 {
+		return
+	}
+	c.fitted = width
+
+	// the plot area starts after the value labels, which termui keeps to
+	// itself, so lay the chart out once to find where it starts. The labels
+	// don't change on the second pass since resampling keeps the extremes
+	c.setSamples(data.Resample(c.Series, 2*(width-1)))
+	originX, ok := c.originX(c.LineChart.Buffer())
+	if !ok {
+		return
+	}
+	c.setSamples(data.Resample(c.Series, 2*(c.InnerBounds().Max.X-originX-1)))
+}
+
+// setSamples plots the samples. Gaps take the value of the sample before
+// them so the chart's bounds aren't thrown off, and are blanked out when
+// both points of a cell are gaps
+func (c *SeriesChart) setSamples(samples []data.Sample) {
+	c.Samples = samples
+	c.Data = make([]float64, len(samples))
+	first := -1
+	for i, s := range samples {
+		switch {
+		case !s.Gap:
+			c.Data[i] = s.Value
+			if first < 0 {
+				first = i
+			}
+		case i > 0:
+			c.Data[i] = c.Data[i-1]
+		}
+	}
+	if first < 0 {
+		c.Data = nil
+		return
+	}
+	for i := 0; i < first; i++ {
+		c.Data[i] = c.Data[first]
+	}
+}
+
+// originX returns the column of the axes' origin in the rendered chart
+func (c *SeriesChart) originX(buf ui.Buffer) (int, bool) {
+	area := c.InnerBounds()
+	originY := area.Max.Y - 2
+	for x := area.Min.X; x < area.Max.X; x++ {
+		if buf.At(x, originY).Ch == ui.ORIGIN {
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+// cells returns the number of plot columns the samples take up, two
+// samples per cell
+func (c *SeriesChart) cells() int {
+	return (len(c.Samples) + 1) / 2
+}
+
+// column returns the plot column of the series point, or -1 for points
+// off the series
+func (c *SeriesChart) column(i int) int {
+	cells := c.cells()
+	if i < 0 || i >= len(c.Series) || cells == 0 {
+		return -1
+	}
+	// series that fit the chart are plotted point by point
+	if len(c.Samples) == len(c.Series) {
+		return i / 2
+	}
+	t0, t1 := c.Series[0][0], c.Series[len(c.Series)-1][0]
+	if t1 <= t0 {
+		return 0
+	}
+	col := int((c.Series[i][0] - t0) / (t1 - t0) * float64(cells))
+	if col >= cells {
+		col = cells - 1
+	}
+	return col
+}
+
+// MoveCursor moves the cursor to the first point of the next column with
+// points, to the left for a negative dir. The cursor starts at the last point
+func (c *SeriesChart) MoveCursor(dir int) {
+	n := len(c.Series)
+	if n == 0 {
+		return
+	}
+	if c.Cursor < 0 {
+		c.Cursor = n - 1
+		return
+	}
+
+	col := c.column(c.Cursor)
+	i := c.Cursor
+	if dir > 0 {
+		for i < n-1 && c.column(i) == col {
+			i++
+		}
+	} else {
+		for i > 0 && c.column(i) == col {
+			i--
+		}
+		for i > 0 && c.column(i-1) == c.column(i) {
+			i--
+		}
+	}
+	c.Cursor = i
+}
+
+// SetCursorTime moves the cursor to the point nearest the timestamp in ms
+func (c *SeriesChart) SetCursorTime(t float64) {
+	n := len(c.Series)
+	if n == 0 {
+		return
+	}
+	i := sort.Search(n, func(i int) bool { return c.Series[i][0] >= t })
+	if i == n || (i > 0 && t-c.Series[i-1][0] < c.Series[i][0]-t) {
+		i--
+	}
+	c.Cursor = i
+}
+
+// SnapCursor moves the cursor to the highest point, or the lowest when high
+// is false
+func (c *SeriesChart) SnapCursor(high bool) {
+	for i, p := range c.Series {
+		if c.Cursor < 0 || (high && p[1] > c.Series[c.Cursor][1]) || (!high && p[1] < c.Series[c.Cursor][1]) {
+			c.Cursor = i
+		}
+	}
+}
+
+// CursorAt moves the cursor to the first point of the column under the
+// screen point, or the point nearest the column when it has none, and
+// returns whether the screen point is on the plot
+func (c *SeriesChart) CursorAt(x, y int) bool {
+	if !image.Pt(x, y).In(c.InnerBounds()) || len(c.Series) == 0 {
+		return false
+	}
+
+	originX, ok := c.originX(c.Buffer())
+	cells := c.cells()
+	col := x - originX - 1
+	if !ok || col < 0 || col >= cells {
+		return false
+	}
+
+	i := sort.Search(len(c.Series), func(i int) bool { return c.column(i) >= col })
+	if i < len(c.Series) && c.column(i) == col {
+		c.Cursor = i
+		return true
+	}
+	t0, t1 := c.Series[0][0], c.Series[len(c.Series)-1][0]
+	c.SetCursorTime(t0 + (float64(col)+0.5)*(t1-t0)/float64(cells))
+	return true
+}
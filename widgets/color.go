@@ -0,0 +1,101 @@
+// Package widgets provides termui widgets for coin stats and charts
+package widgets
+
+import (
+	"math"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/theme"
+)
+
+// current is the theme the widgets are colored with
+var current = theme.Presets["dark"]
+
+// depth is the number of colors the terminal supports
+var depth = theme.Colors8
+
+// SetTheme sets the theme and color depth the widgets are colored with, and
+// the termui defaults. Call it before ui.Init so the background applies
+func SetTheme(t theme.Theme, colorDepth int) {
+	current = t
+	depth = colorDepth
+
+	ui.ColorMap = map[string]ui.Attribute{
+		"fg":        Attr(t.Text),
+		"bg":        Attr(t.Background),
+		"border.fg": Attr(t.Border),
+		"label.fg":  Attr(t.Accent),
+	}
+}
+
+// Theme returns the current theme
+func Theme() theme.Theme {
+	return current
+}
+
+// Attr returns the termui attribute of the theme color at the color depth.
+// Hex colors are approximated with the nearest 256 color palette index as
+// termbox has no truecolor output
+func Attr(c theme.Color) ui.Attribute {
+	return ui.Attribute(c.Index(depth) + 1)
+}
+
+// Color gets the primary color attribute for a color name, 256 color index
+// or hex color, defaulting to the theme's accent color
+func Color(color string) ui.Attribute {
+	if color == "" {
+		return Attr(current.Accent)
+	}
+	return Attr(current.WithAccent(color).Accent)
+}
+
+// TextColor returns the theme's text color
+func TextColor() ui.Attribute {
+	return Attr(current.Text)
+}
+
+// BorderColor returns the theme's border color
+func BorderColor() ui.Attribute {
+	return Attr(current.Border)
+}
+
+// ChangeColor returns the theme's up color for gains and down color for
+// losses
+func ChangeColor(change float64) ui.Attribute {
+	if change < 0 {
+		return Attr(current.Down)
+	}
+	return Attr(current.Up)
+}
+
+// CorrelationColors returns the text and background colors of a
+// correlation, filled with the theme's up or down color when it's strong,
+// in that color when it's moderate, and plain when it's weak or missing
+func CorrelationColors(value float64) (ui.Attribute, ui.Attribute) {
+	switch {
+	case math.Abs(value) >= 0.7:
+		return TileTextColor(), ChangeColor(value)
+	case math.Abs(value) >= 0.3:
+		return ChangeColor(value), ui.ColorDefault
+	}
+	return TextColor(), ui.ColorDefault
+}
+
+// TileTextColor returns the text color for tiles colored with ChangeColor,
+// reversed when the theme has no colors
+func TileTextColor() ui.Attribute {
+	if current.Up.IsDefault() && current.Down.IsDefault() {
+		return ui.ColorDefault | ui.AttrReverse
+	}
+	return ui.ColorBlack
+}
+
+// changeMarkup returns the termui markup color for gains and losses, which
+// only supports the basic color names
+func changeMarkup(change float64) string {
+	c := current.Up
+	if change < 0 {
+		c = current.Down
+	}
+	return "fg-" + c.BasicName()
+}
@@ -0,0 +1,122 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	ui "github.com/gizak/termui"
+)
+
+// Matrix is a widget that shows a correlation matrix with a row and column
+// for each label, and each cell colored by the sign and strength of its
+// correlation
+type Matrix struct {
+	ui.Block
+	Labels []string
+	Values [][]float64 // NaN for missing correlations
+	Footer string      // shown on the last row, ie. key hints
+}
+
+// NewMatrix returns a new correlation matrix
+func NewMatrix() *Matrix {
+	return &Matrix{Block: *ui.NewBlock()}
+}
+
+// Buffer implements Bufferer interface
+func (m *Matrix) Buffer() ui.Buffer {
+	buf := m.Block.Buffer()
+	area := m.InnerBounds()
+	n := len(m.Labels)
+	if n == 0 {
+		return buf
+	}
+
+	labelWidth := 0
+	for _, label := range m.Labels {
+		if len(label) > labelWidth {
+			labelWidth = len(label)
+		}
+	}
+	labelWidth++
+
+	// cells are up to twice as wide as they are tall, keeping the footer row
+	rows := area.Dy() - 2
+	if m.Footer == "" {
+		rows++
+	}
+	cellWidth := clamp((area.Dx()-labelWidth)/n, 6, 12)
+	cellHeight := clamp(rows/n, 1, cellWidth/4)
+
+	text := TextColor()
+	for j, label := range m.Labels {
+		m.setText(buf, area.Min.X+labelWidth+j*cellWidth, area.Min.Y, cellWidth-1, label, text|ui.AttrBold, m.Bg, true)
+	}
+
+	for i, label := range m.Labels {
+		y0 := area.Min.Y + 1 + i*cellHeight
+		m.setText(buf, area.Min.X, y0+(cellHeight-1)/2, labelWidth, label, text|ui.AttrBold, m.Bg, false)
+
+		for j := range m.Labels {
+			value := math.NaN()
+			if i < len(m.Values) && j < len(m.Values[i]) {
+				value = m.Values[i][j]
+			}
+			fg, bg := CorrelationColors(value)
+			if bg == ui.ColorDefault {
+				bg = m.Bg
+			}
+			x0 := area.Min.X + labelWidth + j*cellWidth
+			for y := y0; y < y0+cellHeight; y++ {
+				for x := x0; x < x0+cellWidth-1; x++ {
+					m.set(buf, x, y, ui.Cell{Ch: ' ', Fg: fg, Bg: bg})
+				}
+			}
+
+			label := "n/a"
+			if !math.IsNaN(value) {
+				label = fmt.Sprintf("%+.2f", value)
+			}
+			m.setText(buf, x0, y0+(cellHeight-1)/2, cellWidth-1, label, fg, bg, true)
+		}
+	}
+
+	if m.Footer != "" {
+		m.setText(buf, area.Min.X, area.Max.Y-1, area.Dx(), m.Footer, text, m.Bg, false)
+	}
+
+	return buf
+}
+
+// setText sets the text in the width from x, centered or left aligned, and
+// clipped to the inner area
+func (m *Matrix) setText(buf ui.Buffer, x, y, width int, s string, fg, bg ui.Attribute, center bool) {
+	runes := []rune(s)
+	if len(runes) > width {
+		runes = runes[:width]
+	}
+	if center {
+		x += (width - len(runes)) / 2
+	}
+	for k, r := range runes {
+		m.set(buf, x+k, y, ui.Cell{Ch: r, Fg: fg, Bg: bg})
+	}
+}
+
+// set sets the cell when it's within the inner area
+func (m *Matrix) set(buf ui.Buffer, x, y int, c ui.Cell) {
+	if image.Pt(x, y).In(m.InnerBounds()) {
+		buf.Set(x, y, c)
+	}
+}
+
+// clamp returns the value kept between low and high
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
@@ -0,0 +1,32 @@
+package widgets
+
+import (
+	"fmt"
+
+	ui "github.com/gizak/termui"
+	"github.com/miguelmota/cryptocharts/data"
+	"github.com/miguelmota/cryptocharts/format"
+	cmc "github.com/miguelmota/go-coinmarketcap"
+)
+
+// NewMoversList returns a list widget of the coins and their % change over
+// the change window, with room for count coins
+func NewMoversList(label string, coins []cmc.Coin, window string, count int, primaryColor ui.Attribute) *ui.List {
+	var items []string
+	for i, coin := range coins {
+		change := data.PercentChange(coin, window)
+		items = append(items, fmt.Sprintf("%2d. %-6s %-15s [%s](%s)", i+1, coin.Symbol, format.USD(coin.PriceUsd), format.SignedPercent(change), changeMarkup(change)))
+	}
+	if len(items) == 0 {
+		items = append(items, "none")
+	}
+
+	list := ui.NewList()
+	list.Items = items
+	list.Height = count + 2
+	list.ItemFgColor = TextColor()
+	list.BorderLabel = fmt.Sprintf("%s (%s)", label, window)
+	list.BorderLabelFg = primaryColor
+	list.BorderFg = BorderColor()
+	return list
+}
@@ -0,0 +1,65 @@
+package widgets
+
+import (
+	"fmt"
+	"math"
+
+	ui "github.com/gizak/termui"
+)
+
+// ShareBars is a widget that shows horizontal bars of percentages
+type ShareBars struct {
+	ui.Block
+	Labels    []string
+	Percents  []float64
+	BarColor  ui.Attribute
+	TextColor ui.Attribute
+}
+
+// NewShareBars returns new share bars
+func NewShareBars() *ShareBars {
+	return &ShareBars{Block: *ui.NewBlock(), TextColor: TextColor()}
+}
+
+// Buffer implements Bufferer interface
+func (b *ShareBars) Buffer() ui.Buffer {
+	buf := b.Block.Buffer()
+	area := b.InnerBounds()
+
+	labelWidth := 0
+	for _, label := range b.Labels {
+		if len(label) > labelWidth {
+			labelWidth = len(label)
+		}
+	}
+
+	// leave room for the label and a "100.00%" value
+	barWidth := area.Dx() - labelWidth - 9
+	for i := range b.Labels {
+		y := area.Min.Y + i
+		if y >= area.Max.Y {
+			break
+		}
+
+		line := fmt.Sprintf("%-*s ", labelWidth, b.Labels[i])
+		for j, r := range line {
+			buf.Set(area.Min.X+j, y, ui.Cell{Ch: r, Fg: b.TextColor, Bg: b.Bg})
+		}
+
+		x := area.Min.X + len(line)
+		n := 0
+		if barWidth > 0 {
+			n = int(math.Floor(b.Percents[i]/100*float64(barWidth) + 0.5))
+		}
+		for j := 0; j < n; j++ {
+			buf.Set(x+j, y, ui.Cell{Ch: '█', Fg: b.BarColor, Bg: b.Bg})
+		}
+
+		value := fmt.Sprintf(" %.2f%%", b.Percents[i])
+		for j, r := range value {
+			buf.Set(x+n+j, y, ui.Cell{Ch: r, Fg: b.TextColor, Bg: b.Bg})
+		}
+	}
+
+	return buf
+}
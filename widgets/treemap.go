@@ -0,0 +1,164 @@
+package widgets
+
+import (
+	"math"
+
+	ui "github.com/gizak/termui"
+)
+
+// Treemap is a widget that lays out tiles sized by value as a squarified treemap
+type Treemap struct {
+	ui.Block
+	Tiles []TreemapTile
+}
+
+// TreemapTile is a single treemap tile
+type TreemapTile struct {
+	Label    string
+	Sublabel string
+	Value    float64
+	Fg       ui.Attribute
+	Bg       ui.Attribute
+}
+
+// NewTreemap returns a new treemap
+func NewTreemap() *Treemap {
+	return &Treemap{Block: *ui.NewBlock()}
+}
+
+// Buffer implements Bufferer interface
+func (t *Treemap) Buffer() ui.Buffer {
+	buf := t.Block.Buffer()
+	area := t.InnerBounds()
+
+	values := make([]float64, len(t.Tiles))
+	for i, tile := range t.Tiles {
+		values[i] = tile.Value
+	}
+
+	// terminal cells are about twice as tall as they are wide so lay out
+	// in half cell rows to keep the tiles square on screen
+	rects := squarify(values, rect{w: float64(area.Dx()), h: float64(area.Dy() * 2)})
+
+	for i, r := range rects {
+		x0 := area.Min.X + int(math.Floor(r.x+0.5))
+		x1 := area.Min.X + int(math.Floor(r.x+r.w+0.5))
+		y0 := area.Min.Y + int(math.Floor(r.y/2+0.5))
+		y1 := area.Min.Y + int(math.Floor((r.y+r.h)/2+0.5))
+		if x1 <= x0 || y1 <= y0 {
+			continue
+		}
+
+		tile := t.Tiles[i]
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				ch := ' '
+				// separate neighbouring tiles of the same color
+				if x == x1-1 && x1 < area.Max.X {
+					ch = '▕'
+				}
+				buf.Set(x, y, ui.Cell{Ch: ch, Fg: ui.ColorBlack, Bg: tile.Bg})
+			}
+		}
+
+		lines := []string{tile.Label, tile.Sublabel}
+		for j, line := range lines {
+			y := y0 + (y1-y0-len(lines))/2 + j
+			if y < y0 || y >= y1 {
+				continue
+			}
+			runes := []rune(line)
+			if len(runes) > x1-x0-1 {
+				runes = runes[:x1-x0-1]
+			}
+			x := x0 + (x1-x0-len(runes))/2
+			for k, r := range runes {
+				buf.Set(x+k, y, ui.Cell{Ch: r, Fg: tile.Fg | ui.AttrBold, Bg: tile.Bg})
+			}
+		}
+	}
+
+	return buf
+}
+
+// rect is a treemap rectangle
+type rect struct {
+	x, y, w, h float64
+}
+
+// squarify lays out the values, sorted in descending order, as rectangles
+// filling r with aspect ratios as close to 1 as possible
+func squarify(values []float64, r rect) []rect {
+	rects := make([]rect, len(values))
+
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	if total <= 0 || r.w <= 0 || r.h <= 0 {
+		return rects
+	}
+
+	areas := make([]float64, len(values))
+	for i, v := range values {
+		areas[i] = v * r.w * r.h / total
+	}
+
+	for i := 0; i < len(areas); {
+		side := math.Min(r.w, r.h)
+
+		// grow the row while it improves the worst aspect ratio
+		j := i + 1
+		for j < len(areas) && worstRatio(areas[i:j+1], side) <= worstRatio(areas[i:j], side) {
+			j++
+		}
+
+		rowArea := 0.0
+		for _, a := range areas[i:j] {
+			rowArea += a
+		}
+
+		if r.w >= r.h {
+			// lay out a column along the left side
+			w := rowArea / r.h
+			y := r.y
+			for k := i; k < j; k++ {
+				h := areas[k] / w
+				rects[k] = rect{r.x, y, w, h}
+				y += h
+			}
+			r.x += w
+			r.w -= w
+		} else {
+			// lay out a row along the top side
+			h := rowArea / r.w
+			x := r.x
+			for k := i; k < j; k++ {
+				w := areas[k] / h
+				rects[k] = rect{x, r.y, w, h}
+				x += w
+			}
+			r.y += h
+			r.h -= h
+		}
+
+		i = j
+	}
+
+	return rects
+}
+
+// worstRatio returns the worst aspect ratio of a row of areas laid out along
+// a side of the given length
+func worstRatio(row []float64, side float64) float64 {
+	sum, min, max := 0.0, math.Inf(1), 0.0
+	for _, a := range row {
+		sum += a
+		min = math.Min(min, a)
+		max = math.Max(max, a)
+	}
+	if sum == 0 || min == 0 {
+		return math.Inf(1)
+	}
+	return math.Max(side*side*max/(sum*sum), (sum*sum)/(side*side*min))
+}